@@ -0,0 +1,129 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchOptions configures a Batcher
+type BatchOptions struct {
+	// MaxSize, if set, flushes a batch as soon as it holds MaxSize items and
+	// bounds the size of the batches passed to the func
+	MaxSize int
+
+	// FromLast measures the delay since the last item of a batch instead of
+	// the first one
+	FromLast bool
+}
+
+// Batcher collects items and passes them to its func in batches
+type Batcher[T any] struct {
+	m        sync.Mutex
+	d        time.Duration
+	max      int
+	fromLast bool
+	fn       *Fn
+	do       func([]T)
+
+	items  []T
+	closed bool
+}
+
+// NewBatcher returns a Batcher that passes the items it collected to fn
+// once d has passed since the first item of a batch was added. opts
+// configure the Fn that calls fn.
+func NewBatcher[T any](d time.Duration, fn func([]T), o BatchOptions, opts ...Option) *Batcher[T] {
+	b := &Batcher[T]{
+		d:        d,
+		max:      o.MaxSize,
+		fromLast: o.FromLast,
+		do:       fn,
+	}
+
+	var call func()
+	if fn != nil {
+		call = b.call
+	}
+	b.fn = NewFn(d, call, opts...)
+	return b
+}
+
+// Add adds item to the batch, flushing the batch right away if it is full
+func (b *Batcher[T]) Add(item T) error {
+	b.m.Lock()
+	if b.closed {
+		b.m.Unlock()
+		return ErrClosed
+	}
+
+	b.items = append(b.items, item)
+	if b.max > 0 && len(b.items) >= b.max {
+		b.m.Unlock()
+		debug("batch: flushing full batch")
+		return b.fn.callNow()
+	}
+	defer b.m.Unlock()
+
+	if len(b.items) > 1 && !b.fromLast {
+		return nil
+	}
+
+	if err := b.fn.ResetDelay(b.d); err != nil {
+		b.items = b.items[:len(b.items)-1]
+		return err
+	}
+	return nil
+}
+
+// call passes the collected items to the func, MaxSize items at a time
+func (b *Batcher[T]) call() {
+	b.m.Lock()
+	items := b.items
+	b.items = nil
+	b.m.Unlock()
+
+	for len(items) > 0 {
+		n := len(items)
+		if b.max > 0 {
+			n = min(n, b.max)
+		}
+		b.do(items[:n])
+		items = items[n:]
+	}
+}
+
+// Flush passes the collected items to the func right away; returns false if
+// there were none
+func (b *Batcher[T]) Flush() bool {
+	return b.fn.FlushIfPending()
+}
+
+// Len returns the number of items collected so far
+func (b *Batcher[T]) Len() int {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return len(b.items)
+}
+
+// Close flushes the collected items and makes Add return ErrClosed from
+// then on. Calling Close again returns ErrClosed.
+func (b *Batcher[T]) Close() error {
+	b.m.Lock()
+	if b.closed {
+		b.m.Unlock()
+		return ErrClosed
+	}
+	b.closed = true
+	b.m.Unlock()
+
+	debug("batch: closing")
+	b.fn.FlushIfPending()
+	return b.fn.Close()
+}
+
+// Wait blocks until the pending batch is passed to the func unless ctx is
+// done first
+func (b *Batcher[T]) Wait(ctx context.Context) error {
+	return b.fn.Wait(ctx)
+}
@@ -0,0 +1,130 @@
+package delayed_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed"
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// batches keeps the batches a Batcher passed on
+type batches struct {
+	m   sync.Mutex
+	all [][]int
+}
+
+func (r *batches) record(items []int) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.all = append(r.all, append([]int(nil), items...))
+}
+
+func (r *batches) recorded() [][]int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([][]int(nil), r.all...)
+}
+
+func TestBatcher(t *testing.T) {
+	c := clock.NewFake()
+	r := &batches{}
+	b := delayed.NewBatcher(time.Second, r.record, delayed.BatchOptions{}, delayed.WithClock(c))
+
+	b.Add(1)
+	c.Advance(500 * time.Millisecond)
+	b.Add(2)
+	b.Add(3)
+	assert.Equal(t, 3, b.Len())
+
+	// the delay is measured since the first item
+	c.Advance(500 * time.Millisecond)
+	b.Wait(context.Background())
+	assert.Equal(t, [][]int{{1, 2, 3}}, r.recorded())
+	assert.Equal(t, 0, b.Len())
+
+	b.Add(4)
+	c.Advance(time.Second)
+	b.Wait(context.Background())
+	assert.Equal(t, [][]int{{1, 2, 3}, {4}}, r.recorded())
+}
+
+func TestBatcher_from_last(t *testing.T) {
+	c := clock.NewFake()
+	r := &batches{}
+	b := delayed.NewBatcher(time.Second, r.record, delayed.BatchOptions{FromLast: true}, delayed.WithClock(c))
+
+	b.Add(1)
+	c.Advance(500 * time.Millisecond)
+	b.Add(2)
+	c.Advance(500 * time.Millisecond)
+	assert.Empty(t, r.recorded(), "the delay must restart with every item")
+
+	c.Advance(500 * time.Millisecond)
+	b.Wait(context.Background())
+	assert.Equal(t, [][]int{{1, 2}}, r.recorded())
+}
+
+func TestBatcher_max_size(t *testing.T) {
+	c := clock.NewFake()
+	r := &batches{}
+	b := delayed.NewBatcher(time.Second, r.record, delayed.BatchOptions{MaxSize: 2}, delayed.WithClock(c))
+
+	b.Add(1)
+	assert.Empty(t, r.recorded())
+	b.Add(2)
+	assert.Equal(t, [][]int{{1, 2}}, r.recorded(), "a full batch must be flushed right away")
+
+	b.Add(3)
+	c.Advance(time.Second)
+	b.Wait(context.Background())
+	assert.Equal(t, [][]int{{1, 2}, {3}}, r.recorded())
+}
+
+func TestBatcher_close(t *testing.T) {
+	r := &batches{}
+	b := delayed.NewBatcher(time.Hour, r.record, delayed.BatchOptions{})
+
+	assert.False(t, b.Flush(), "nothing to flush")
+	b.Add(1)
+	b.Add(2)
+	assert.NoError(t, b.Close())
+	assert.Equal(t, [][]int{{1, 2}}, r.recorded(), "close must flush the collected items")
+
+	assert.Equal(t, delayed.ErrClosed, b.Add(3))
+	assert.Equal(t, delayed.ErrClosed, b.Close())
+	assert.Equal(t, [][]int{{1, 2}}, r.recorded())
+}
+
+func TestBatcher_concurrent_adds(t *testing.T) {
+	r := &batches{}
+	b := delayed.NewBatcher(10*time.Millisecond, r.record, delayed.BatchOptions{MaxSize: 10})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b.Add(i)
+		}(i)
+	}
+	wg.Wait()
+	b.Close()
+	b.Wait(context.Background())
+
+	seen := 0
+	for _, batch := range r.recorded() {
+		assert.True(t, len(batch) <= 10, "batches must not exceed MaxSize")
+		seen += len(batch)
+	}
+	assert.Equal(t, 100, seen)
+}
+
+func TestBatcher_nil_func(t *testing.T) {
+	b := delayed.NewBatcher[int](time.Second, nil, delayed.BatchOptions{})
+	assert.Equal(t, delayed.ErrNilFunc, b.Add(1))
+	assert.Equal(t, 0, b.Len())
+}
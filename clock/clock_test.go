@@ -0,0 +1,42 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReal_after_func(t *testing.T) {
+	c := New()
+
+	fired := make(chan struct{})
+	timer := c.AfterFunc(10*time.Millisecond, func() { close(fired) })
+	assert.Nil(t, timer.C())
+
+	select {
+	case <-fired:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("timer did not fire")
+	}
+	assert.False(t, timer.Stop(), "fired timer must not be stopped")
+}
+
+func TestReal_timer_reset(t *testing.T) {
+	c := New()
+	start := c.Now()
+
+	timer := c.NewTimer(time.Second)
+	assert.True(t, timer.Reset(10*time.Millisecond))
+
+	<-timer.C()
+	assert.True(t, c.Since(start) < time.Second)
+}
+
+func TestReal_ticker(t *testing.T) {
+	ticker := New().NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+	<-ticker.C()
+	<-ticker.C()
+}
@@ -0,0 +1,218 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when it is advanced
+type Fake struct {
+	m      sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake returns a Fake set to an arbitrary non-zero time
+func NewFake() *Fake {
+	return NewFakeAt(time.Date(1984, time.April, 4, 0, 0, 0, 0, time.UTC))
+}
+
+// NewFakeAt returns a Fake set to t
+func NewFakeAt(t time.Time) *Fake {
+	c := &Fake{now: t}
+	c.cond = sync.NewCond(&c.m)
+	return c
+}
+
+// Now returns the time of the clock
+func (c *Fake) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+// Since returns the time elapsed on the clock since t
+func (c *Fake) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Sleep blocks until the clock is advanced by d
+func (c *Fake) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// After sends the time on the returned channel once the clock is advanced
+// by d
+func (c *Fake) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// AfterFunc calls fn in its own goroutine once the clock is advanced by d
+func (c *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return c.add(&fakeTimer{c: c, fn: fn}, d)
+}
+
+// NewTimer returns a Timer that fires once the clock is advanced by d
+func (c *Fake) NewTimer(d time.Duration) Timer {
+	return c.add(&fakeTimer{c: c, ch: make(chan time.Time, 1)}, d)
+}
+
+// NewTicker returns a Ticker that ticks every time the clock is advanced by
+// d; like time.Ticker it drops ticks that are not received in time
+func (c *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return fakeTicker{c.add(&fakeTimer{c: c, ch: make(chan time.Time, 1), period: d}, d)}
+}
+
+// Advance moves the clock forward by d, firing the timers that become due in
+// the order of their deadlines
+func (c *Fake) Advance(d time.Duration) {
+	c.m.Lock()
+	end := c.now.Add(d)
+
+	for len(c.timers) > 0 && !c.timers[0].until.After(end) {
+		t := c.timers[0]
+		c.remove(t)
+		c.now = t.until
+
+		if t.period > 0 {
+			t.until = t.until.Add(t.period)
+			c.insert(t)
+		}
+		t.fire(c.now)
+	}
+
+	c.now = end
+	c.m.Unlock()
+}
+
+// BlockUntilTimers blocks until at least n timers are pending
+func (c *Fake) BlockUntilTimers(n int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// Pending returns the deadlines of the pending timers in the order they fire
+func (c *Fake) Pending() []time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	deadlines := make([]time.Time, len(c.timers))
+	for i, t := range c.timers {
+		deadlines[i] = t.until
+	}
+	return deadlines
+}
+
+func (c *Fake) add(t *fakeTimer, d time.Duration) *fakeTimer {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.schedule(t, c.now.Add(d))
+	return t
+}
+
+// schedule makes t due at until; like the time package it fires t right away
+// if until is not in the future
+func (c *Fake) schedule(t *fakeTimer, until time.Time) {
+	t.until = until
+	if until.After(c.now) {
+		c.insert(t)
+		return
+	}
+
+	if t.period > 0 {
+		t.until = c.now.Add(t.period)
+		c.insert(t)
+	}
+	t.fire(c.now)
+}
+
+// insert keeps the timers sorted by deadline, timers with the same deadline
+// fire in the order they were added
+func (c *Fake) insert(t *fakeTimer) {
+	i := sort.Search(len(c.timers), func(i int) bool {
+		return c.timers[i].until.After(t.until)
+	})
+
+	c.timers = append(c.timers, nil)
+	copy(c.timers[i+1:], c.timers[i:])
+	c.timers[i] = t
+	c.cond.Broadcast()
+}
+
+// remove returns false if t is not pending
+func (c *Fake) remove(t *fakeTimer) bool {
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer is a Timer that is also used by tickers when period is set
+type fakeTimer struct {
+	c      *Fake
+	until  time.Time
+	period time.Duration
+
+	fn func()
+	ch chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.c.m.Lock()
+	defer t.c.m.Unlock()
+	return t.c.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.c.m.Lock()
+	defer t.c.m.Unlock()
+
+	pending := t.c.remove(t)
+	if t.period > 0 {
+		t.period = d
+	}
+	t.c.schedule(t, t.c.now.Add(d))
+	return pending
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	if t.fn != nil {
+		go t.fn()
+		return
+	}
+
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
+
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
+
+func (t fakeTicker) Reset(d time.Duration) {
+	t.fakeTimer.Reset(d)
+}
@@ -0,0 +1,122 @@
+package clock
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake_now(t *testing.T) {
+	start := time.Date(2018, time.November, 27, 0, 0, 0, 0, time.UTC)
+	c := NewFakeAt(start)
+	assert.Equal(t, start, c.Now())
+
+	c.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), c.Now())
+	assert.Equal(t, time.Hour, c.Since(start))
+	assert.False(t, NewFake().Now().IsZero())
+}
+
+func TestFake_after_func(t *testing.T) {
+	c := NewFake()
+
+	var calls int32
+	fired := make(chan struct{}, 1)
+	c.AfterFunc(time.Second, func() {
+		atomic.AddInt32(&calls, 1)
+		fired <- struct{}{}
+	})
+
+	c.Advance(999 * time.Millisecond)
+	assert.Len(t, c.Pending(), 1)
+
+	c.Advance(time.Millisecond)
+	<-fired
+	assert.Empty(t, c.Pending())
+
+	c.Advance(time.Hour)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "must fire once")
+}
+
+func TestFake_due_timers_fire_right_away(t *testing.T) {
+	c := NewFake()
+
+	fired := make(chan struct{}, 2)
+	c.AfterFunc(0, func() { fired <- struct{}{} })
+	<-fired
+
+	timer := c.AfterFunc(time.Second, func() { fired <- struct{}{} })
+	timer.Reset(-time.Second)
+	<-fired
+	assert.Empty(t, c.Pending(), "due timers must not wait for Advance")
+
+	ch := c.NewTimer(0).C()
+	assert.Equal(t, c.Now(), <-ch)
+}
+
+func TestFake_timer_stop_reset(t *testing.T) {
+	c := NewFake()
+
+	timer := c.NewTimer(time.Second)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop(), "stopped timer must not be stopped again")
+
+	c.Advance(time.Second)
+	assert.Len(t, timer.C(), 0, "stopped timer must not fire")
+
+	assert.False(t, timer.Reset(time.Second), "stopped timer was not pending")
+	c.Advance(time.Second)
+	assert.Equal(t, c.Now(), <-timer.C())
+	assert.False(t, timer.Reset(time.Second), "fired timer was not pending")
+}
+
+func TestFake_order(t *testing.T) {
+	c := NewFake()
+	start := c.Now()
+
+	late := c.NewTimer(2 * time.Second)
+	early := c.NewTimer(time.Second)
+	assert.Equal(t, []time.Time{start.Add(time.Second), start.Add(2 * time.Second)}, c.Pending())
+
+	c.Advance(3 * time.Second)
+	assert.Equal(t, start.Add(time.Second), <-early.C(), "must fire at its deadline")
+	assert.Equal(t, start.Add(2*time.Second), <-late.C(), "must fire at its deadline")
+}
+
+func TestFake_ticker(t *testing.T) {
+	c := NewFake()
+	start := c.Now()
+
+	ticker := c.NewTicker(time.Second)
+	c.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-ticker.C())
+
+	// ticks that are not received are dropped
+	c.Advance(3 * time.Second)
+	assert.Equal(t, start.Add(2*time.Second), <-ticker.C())
+	assert.Len(t, ticker.C(), 0)
+
+	ticker.Reset(2 * time.Second)
+	c.Advance(2 * time.Second)
+	assert.Equal(t, start.Add(6*time.Second), <-ticker.C())
+
+	ticker.Stop()
+	c.Advance(time.Hour)
+	assert.Len(t, ticker.C(), 0, "stopped ticker must not tick")
+}
+
+func TestFake_block_until_timers(t *testing.T) {
+	c := NewFake()
+
+	slept := make(chan struct{})
+	go func() {
+		c.Sleep(time.Second)
+		close(slept)
+	}()
+
+	c.BlockUntilTimers(1)
+	c.Advance(time.Second)
+	<-slept
+}
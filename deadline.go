@@ -0,0 +1,133 @@
+package delayed
+
+import (
+	"time"
+)
+
+// CallAt executes fn at t and returns a handle to the function so that it
+// can be cancelled or overridden. See ResetAt for when fn is called.
+func CallAt(t time.Time, fn func(), opts ...Option) (*Fn, error) {
+	f := newFn(0, plain(fn), opts)
+	if err := f.ResetAt(t); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// ResetAt resets the delay so that the func is called at t, or right away if
+// t is in the past. The delay is measured when ResetAt is called and the
+// deadline is derived from it, so neither the run nor Remaining move if the
+// wall clock jumps afterwards.
+func (f *Fn) ResetAt(t time.Time) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	now := f.now()
+	f.d = nonNegative(t.Sub(now))
+	if err := f.check(); err != nil {
+		return err
+	}
+
+	// unlike t, now carries the monotonic reading of the clock
+	f.scheduleAt(now.Add(f.d))
+	return nil
+}
+
+// Extend postpones the pending run by delta, bringing it forward if delta is
+// negative; returns false if nothing is pending
+func (f *Fn) Extend(delta time.Duration) bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.paused {
+		f.remaining = nonNegative(f.remaining + delta)
+		return true
+	}
+
+	if !f.hold() {
+		return false
+	}
+
+	debug("extending delayed call by %v", delta)
+	f.scheduleAt(f.deadline.Add(delta))
+	return true
+}
+
+// Shorten brings the pending run forward by delta; returns false if nothing
+// is pending
+func (f *Fn) Shorten(delta time.Duration) bool {
+	return f.Extend(-delta)
+}
+
+// ResetIfEarlier resets the delay to d only if that makes the run due earlier
+// than the pending one or nothing is pending; returns whether it did so
+func (f *Fn) ResetIfEarlier(d time.Duration) (bool, error) {
+	return f.resetIf(d, time.Time.Before)
+}
+
+// ResetIfLater resets the delay to d only if that makes the run due later
+// than the pending one or nothing is pending; returns whether it did so
+func (f *Fn) ResetIfLater(d time.Duration) (bool, error) {
+	return f.resetIf(d, time.Time.After)
+}
+
+// ReplaceFunc replaces the func to be invoked without changing when the
+// pending run is due. If nothing is pending fn is used by the next Call.
+func (f *Fn) ReplaceFunc(fn func()) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	switch {
+	case f.closed:
+		return ErrClosed
+	case fn == nil:
+		return ErrNilFunc
+	}
+
+	f.fn = plain(fn)
+	if f.hold() {
+		f.scheduleAt(f.deadline)
+	}
+	return nil
+}
+
+// resetIf resets the delay to d if the run becomes due at a time that is
+// preferred to the due time of the pending one
+func (f *Fn) resetIf(d time.Duration, preferred func(due, pending time.Time) bool) (bool, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.closed {
+		return false, ErrClosed
+	}
+
+	// the jittered delay is compared so that it is the one scheduled
+	due := f.now().Add(f.jittered(d))
+	if pending, ok := f.due(); ok && !preferred(due, pending) {
+		return false, nil
+	}
+
+	f.d = d
+	if err := f.check(); err != nil {
+		return false, err
+	}
+
+	f.scheduleAt(due)
+	return true, nil
+}
+
+// due returns when the pending or paused run is due
+func (f *Fn) due() (time.Time, bool) {
+	switch {
+	case f.paused:
+		return f.now().Add(f.remaining), true
+	case f.pending:
+		return f.deadline, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,205 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+	"github.com/sthaha/delayed/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtend(t *testing.T) {
+	fn := &Fn{}
+	assert.False(t, fn.Extend(time.Second), "nothing is pending")
+
+	fn.Reset(50*time.Millisecond, func() {})
+	deadline := fn.Deadline()
+
+	assert.True(t, fn.Extend(20*time.Millisecond))
+	assert.Equal(t, deadline.Add(20*time.Millisecond), fn.Deadline())
+
+	assert.True(t, fn.Shorten(30*time.Millisecond))
+	assert.Equal(t, deadline.Add(-10*time.Millisecond), fn.Deadline())
+
+	fn.Pause()
+	assert.True(t, fn.Extend(time.Second))
+	assert.True(t, fn.Remaining() > time.Second)
+	fn.Cancel()
+}
+
+func TestShorten_past_deadline(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(time.Second, s.Hook(testutils.Args{"version": 1}))
+
+	assert.True(t, fn.Shorten(2*time.Second))
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must run right away")
+}
+
+func TestResetIfEarlier(t *testing.T) {
+	fn := NewFn(0, func() {})
+
+	ok, err := fn.ResetIfEarlier(50 * time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, ok, "must be scheduled when nothing is pending")
+	deadline := fn.Deadline()
+
+	ok, _ = fn.ResetIfEarlier(time.Second)
+	assert.False(t, ok, "later deadline must be ignored")
+	assert.Equal(t, deadline, fn.Deadline())
+
+	ok, _ = fn.ResetIfEarlier(10 * time.Millisecond)
+	assert.True(t, ok)
+	assert.True(t, fn.Deadline().Before(deadline))
+
+	_, err = fn.ResetIfEarlier(-1)
+	assert.Error(t, err, "invalid duration must return error")
+}
+
+func TestResetIfLater(t *testing.T) {
+	fn := NewFn(0, func() {})
+	fn.ResetDelay(50 * time.Millisecond)
+	deadline := fn.Deadline()
+
+	ok, _ := fn.ResetIfLater(10 * time.Millisecond)
+	assert.False(t, ok, "earlier deadline must be ignored")
+	assert.Equal(t, deadline, fn.Deadline())
+
+	ok, _ = fn.ResetIfLater(time.Second)
+	assert.True(t, ok)
+	assert.True(t, fn.Deadline().After(deadline))
+	fn.Cancel()
+}
+
+func TestResetIfEarlier_producers(t *testing.T) {
+	s := &testutils.Spy{}
+	fn := NewFn(0, s.Hook(testutils.Args{"version": 1}))
+
+	start := time.Now()
+	wg := &sync.WaitGroup{}
+	for i := 5; i >= 1; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn.ResetIfEarlier(time.Duration(20*i) * time.Millisecond)
+		}(i)
+	}
+	wg.Wait()
+
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must be called once")
+	assert.True(t, time.Since(start) < 60*time.Millisecond, "must run at the earliest deadline")
+}
+
+func TestReplaceFunc(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(40*time.Millisecond, s.Hook(testutils.Args{"version": 1}))
+	deadline := fn.Deadline()
+
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, fn.ReplaceFunc(s.Hook(testutils.Args{"version": 2})))
+	assert.Equal(t, deadline, fn.Deadline(), "deadline must not move")
+
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must be called once")
+	assert.Equal(t, 2, s.Args()["version"])
+
+	var invalidFn func()
+	assert.Error(t, fn.ReplaceFunc(invalidFn))
+}
+
+func TestCallAt(t *testing.T) {
+	s := &testutils.Spy{}
+	at := time.Now().Add(30 * time.Millisecond)
+
+	fn, err := CallAt(at, s.Hook(testutils.Args{"version": 1}))
+	assert.NoError(t, err, "must be created")
+	assert.True(t, at.Equal(fn.Deadline()), "deadline must be at")
+
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must be called once")
+	assert.False(t, fn.LastFired().Before(at), "must not run before t")
+}
+
+func TestCallAt_past(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, err := CallAt(time.Now().Add(-time.Hour), s.Hook(testutils.Args{"version": 1}))
+	assert.NoError(t, err, "past t must not be an error")
+
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must run right away")
+
+	var invalidFn func()
+	_, err = CallAt(time.Now(), invalidFn)
+	assert.Error(t, err, "invoking CallAt with invalid args must return error")
+}
+
+func TestResetAt_past_fake_clock(t *testing.T) {
+	c := clock.NewFake()
+	s := &testutils.Spy{}
+	fn := NewFn(time.Hour, s.Hook(testutils.Args{"version": 1}), WithClock(c))
+
+	// must fire without advancing the clock, as with the time package
+	assert.NoError(t, fn.ResetAt(c.Now().Add(-time.Hour)))
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must run right away")
+
+	assert.NoError(t, fn.ResetDelay(0))
+	fn.Wait(context.Background())
+	assert.Equal(t, 2, s.Called(), "must run right away")
+}
+
+// jumpClock is a Fake whose wall clock can jump without moving its timers
+type jumpClock struct {
+	*clock.Fake
+
+	m    sync.Mutex
+	skew time.Duration
+}
+
+func (c *jumpClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.Fake.Now().Add(c.skew)
+}
+
+func (c *jumpClock) jump(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.skew += d
+}
+
+func TestResetAt_wall_clock_jump(t *testing.T) {
+	c := &jumpClock{Fake: clock.NewFake()}
+	s := &testutils.Spy{}
+	fn := NewFn(time.Hour, s.Hook(testutils.Args{"version": 1}), WithClock(c))
+
+	assert.NoError(t, fn.ResetAt(c.Now().Add(time.Second)))
+	c.jump(time.Hour)
+
+	c.Advance(999 * time.Millisecond)
+	assert.Equal(t, 0, s.Called(), "the run must not move with the wall clock")
+
+	c.Advance(time.Millisecond)
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must be called once")
+}
+
+func TestResetAt_wall_clock(t *testing.T) {
+	fn := NewFn(time.Hour, func() {})
+	defer fn.Cancel()
+
+	// Round(0) strips the monotonic reading leaving only the wall clock
+	at := time.Now().Add(time.Hour).Round(0)
+	assert.NoError(t, fn.ResetAt(at))
+
+	// a jump changes wall readings only; Remaining ignores it as long as
+	// the deadline carries a monotonic reading, which String prints as m=
+	deadline := fn.Deadline()
+	assert.Contains(t, deadline.String(), "m=", "deadline must keep the monotonic reading")
+	assert.True(t, fn.Remaining() <= time.Hour)
+	assert.WithinDuration(t, at, deadline, time.Millisecond)
+}
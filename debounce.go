@@ -0,0 +1,154 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Edge is the edge of a burst of triggers on which a Debouncer calls its func
+type Edge int
+
+const (
+	// TrailingEdge calls the func once no trigger arrived for the wait
+	TrailingEdge Edge = 1 << iota
+
+	// LeadingEdge calls the func on the first trigger of a burst and ignores
+	// the rest of the burst, unless MaxWait has passed since the last call
+	LeadingEdge
+
+	// BothEdges calls the func on the first trigger of a burst and once more
+	// after the burst if it had further triggers
+	BothEdges = LeadingEdge | TrailingEdge
+)
+
+// DebounceOptions configures a Debouncer
+type DebounceOptions struct {
+	// Edge is TrailingEdge unless set
+	Edge Edge
+
+	// MaxWait, if set, bounds how long the func may be put off while triggers
+	// keep arriving; it is raised to the wait if shorter
+	MaxWait time.Duration
+}
+
+// Debouncer coalesces a burst of triggers, i.e. triggers that are less than
+// the wait apart, into a single call of its func
+type Debouncer struct {
+	m       sync.Mutex
+	wait    time.Duration
+	maxWait time.Duration
+	edge    Edge
+	fn      *Fn
+	do      func()
+
+	// last is when Trigger was last called and since is when the func was
+	// last called or, if later, when the current burst started
+	last  time.Time
+	since time.Time
+}
+
+// NewDebouncer returns a Debouncer that calls fn once per burst of triggers
+// that are less than wait apart. opts configure the Fn that calls fn.
+func NewDebouncer(wait time.Duration, fn func(), o DebounceOptions, opts ...Option) *Debouncer {
+	if o.Edge == 0 {
+		o.Edge = TrailingEdge
+	}
+
+	if o.MaxWait > 0 && o.MaxWait < wait {
+		o.MaxWait = wait
+	}
+
+	d := &Debouncer{
+		wait:    wait,
+		maxWait: o.MaxWait,
+		edge:    o.Edge,
+		do:      fn,
+	}
+
+	var call func()
+	if fn != nil {
+		call = d.call
+	}
+	d.fn = NewFn(wait, call, opts...)
+	return d
+}
+
+// Trigger records an event. The func is called right away if it starts a
+// burst and the Debouncer fires on the LeadingEdge, otherwise it is scheduled
+// for the end of the burst if the Debouncer fires on the TrailingEdge. With a
+// MaxWait the func is also called once MaxWait has passed since it was last
+// called, on the LeadingEdge right away and on the TrailingEdge by the timer.
+func (d *Debouncer) Trigger() error {
+	d.m.Lock()
+	now := d.fn.now()
+	quiet := d.last.IsZero() || now.Sub(d.last) >= d.wait
+	d.last = now
+	if quiet {
+		d.since = now
+	}
+
+	overdue := d.maxWait > 0 && now.Sub(d.since) >= d.maxWait
+	if d.edge&LeadingEdge != 0 && (quiet || (overdue && d.edge&TrailingEdge == 0)) {
+		// starts the next MaxWait period so that concurrent triggers are
+		// ignored
+		d.since = now
+		d.m.Unlock()
+		debug("debounce: leading edge")
+		return d.fn.callNow()
+	}
+	defer d.m.Unlock()
+
+	if d.edge&TrailingEdge == 0 {
+		return nil
+	}
+
+	delay := d.wait
+	if d.maxWait > 0 {
+		delay = min(delay, nonNegative(d.since.Add(d.maxWait).Sub(now)))
+	}
+	return d.fn.ResetDelay(delay)
+}
+
+// call calls the func and starts the next MaxWait period
+func (d *Debouncer) call() {
+	d.m.Lock()
+	d.since = d.fn.now()
+	d.m.Unlock()
+
+	d.do()
+}
+
+// idle returns whether the burst is over and no call is pending
+func (d *Debouncer) idle() bool {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if d.fn.now().Sub(d.last) < d.wait {
+		return false
+	}
+
+	switch d.fn.State() {
+	case StatePending, StatePaused:
+		return false
+	}
+	return true
+}
+
+// Flush calls the func right away if a trailing call is pending; returns
+// whether it did so
+func (d *Debouncer) Flush() bool {
+	return d.fn.FlushIfPending()
+}
+
+// Cancel cancels the pending trailing call; returns false if nothing is
+// pending
+func (d *Debouncer) Cancel() bool {
+	return d.fn.Cancel()
+}
+
+// Wait blocks until the pending call completes or is cancelled unless ctx
+// is done first
+func (d *Debouncer) Wait(ctx context.Context) error {
+	return d.fn.Wait(ctx)
+}
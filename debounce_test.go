@@ -0,0 +1,158 @@
+package delayed_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed"
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// burst triggers d n times, advancing c by step after each trigger
+func burst(d *delayed.Debouncer, c *clock.Fake, n int, step time.Duration) {
+	for i := 0; i < n; i++ {
+		d.Trigger()
+		c.Advance(step)
+	}
+}
+
+func TestDebouncer_trailing(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	d := delayed.NewDebouncer(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{}, delayed.WithClock(c))
+
+	burst(d, c, 5, 500*time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls), "must wait for the burst to end")
+
+	c.Advance(500 * time.Millisecond)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDebouncer_leading(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	d := delayed.NewDebouncer(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{Edge: delayed.LeadingEdge}, delayed.WithClock(c))
+
+	d.Trigger()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "must call on the first trigger")
+
+	burst(d, c, 5, 500*time.Millisecond)
+	c.Advance(time.Second)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "must ignore the rest of the burst")
+
+	d.Trigger()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "must call on the next burst")
+}
+
+func TestDebouncer_leading_steady_stream(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	d := delayed.NewDebouncer(100*time.Millisecond, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{Edge: delayed.LeadingEdge}, delayed.WithClock(c))
+
+	// triggers every 50ms for a second are a single burst
+	burst(d, c, 20, 50*time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// MaxWait keeps a steady stream from suppressing calls for good
+	d = delayed.NewDebouncer(100*time.Millisecond, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{Edge: delayed.LeadingEdge, MaxWait: 300 * time.Millisecond}, delayed.WithClock(c))
+	burst(d, c, 20, 50*time.Millisecond)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls), "must call at 0, 300, 600 and 900ms of the second stream")
+}
+
+func TestDebouncer_both_edges(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	d := delayed.NewDebouncer(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{Edge: delayed.BothEdges}, delayed.WithClock(c))
+
+	// a single trigger calls on the leading edge only
+	d.Trigger()
+	c.Advance(2 * time.Second)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	burst(d, c, 5, 500*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	c.Advance(500 * time.Millisecond)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestDebouncer_flush_and_cancel(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	d := delayed.NewDebouncer(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{}, delayed.WithClock(c))
+
+	assert.False(t, d.Flush(), "nothing to flush")
+	d.Trigger()
+	assert.True(t, d.Flush())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	d.Trigger()
+	assert.True(t, d.Cancel())
+	assert.False(t, d.Cancel(), "nothing to cancel")
+	c.Advance(time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDebouncer_concurrent_triggers(t *testing.T) {
+	var calls int32
+	d := delayed.NewDebouncer(200*time.Millisecond, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{Edge: delayed.BothEdges})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.Trigger()
+		}()
+	}
+	wg.Wait()
+
+	time.Sleep(400 * time.Millisecond)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestDebouncer_max_wait(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	d := delayed.NewDebouncer(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{MaxWait: 2 * time.Second}, delayed.WithClock(c))
+
+	// a trigger every 500ms would put the call off forever without MaxWait
+	burst(d, c, 4, 500*time.Millisecond)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "must call once MaxWait has passed")
+
+	burst(d, c, 4, 500*time.Millisecond)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "must call again after another MaxWait")
+
+	c.Advance(time.Second)
+	d.Wait(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "must not call without further triggers")
+}
+
+func TestDebouncer_max_wait_leading(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	d := delayed.NewDebouncer(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.DebounceOptions{Edge: delayed.LeadingEdge, MaxWait: 2 * time.Second}, delayed.WithClock(c))
+
+	burst(d, c, 5, 500*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "must call on the first trigger and after MaxWait")
+}
@@ -1,10 +1,12 @@
 package delayed
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"sync"
 	"time"
 
+	"github.com/sthaha/delayed/clock"
 	"github.com/sthaha/delayed/testutils"
 )
 
@@ -21,22 +23,76 @@ type Callable interface {
 type Fn struct {
 	m  sync.Mutex
 	d  time.Duration
-	fn func()
+	fn func(context.Context) error
 
-	t *time.Timer
+	onError    func(error)
+	panics     PanicPolicy
+	asyncFlush bool
+	overlap    OverlapPolicy
+	jitter     Jitter
+	random     func() float64
+	ex         Executor
+	obs        Observer
+	timeout    time.Duration
+	last       *Result
+
+	// serial is held by the run in progress unless runs may overlap
+	serial sync.Mutex
+
+	// ctx is the parent of every run; unbind detaches the cancellation
+	// registered on it
+	ctx    context.Context
+	unbind func() bool
+
+	clock clock.Clock
+	t     clock.Timer
+	stop  context.CancelFunc
+
+	// inflight holds the context cancel funcs of the runs in progress by
+	// their gen
+	inflight map[uint64]context.CancelFunc
+
+	// gen identifies the latest scheduled run; done is closed and cleared
+	// once no run is pending or running
+	gen     uint64
+	pending bool
+	running int
+	done    chan struct{}
+
+	deadline  time.Time
+	cancelled bool
+	closed    bool
+
+	// paused is true while a pending run is suspended with remaining left;
+	// held is true while the timer of a pending run is stopped to schedule
+	// it again
+	paused    bool
+	held      bool
+	remaining time.Duration
+
+	fires     int
+	lastFired time.Time
 }
 
 var debug = testutils.Logger("delayed")
 
 // NewFn returns an instance of delayed Fn
-func NewFn(d time.Duration, fn func()) *Fn {
-	return &Fn{d: d, fn: fn}
+func NewFn(d time.Duration, fn func(), opts ...Option) *Fn {
+	return newFn(d, plain(fn), opts)
+}
+
+func newFn(d time.Duration, fn func(context.Context) error, opts []Option) *Fn {
+	f := &Fn{d: d, fn: fn}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // Call executes a fn after the duration and returns a handle to the
 // function so that it can be cancelled or overridden
-func Call(d time.Duration, fn func()) (*Fn, error) {
-	f := &Fn{d: d, fn: fn}
+func Call(d time.Duration, fn func(), opts ...Option) (*Fn, error) {
+	f := newFn(d, plain(fn), opts)
 	if err := f.Call(); err != nil {
 		return nil, err
 	}
@@ -44,13 +100,28 @@ func Call(d time.Duration, fn func()) (*Fn, error) {
 	return f, nil
 }
 
+// CallContext executes fn after the duration unless ctx is done before that.
+// fn receives a context that is cancelled when ctx is done or when the run is
+// superseded by a Reset or Cancel.
+func CallContext(ctx context.Context, d time.Duration, fn func(context.Context), opts ...Option) (*Fn, error) {
+	f := newFn(d, nil, opts)
+	if err := f.ResetContext(ctx, d, fn); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
 // ResetFunc resets func to be invoked
 func (f *Fn) ResetFunc(fn func()) error {
 	f.m.Lock()
 	defer f.m.Unlock()
 
-	f.cancel()
-	f.fn = fn
+	if f.closed {
+		return ErrClosed
+	}
+
+	f.fn = plain(fn)
 	return f.call()
 }
 
@@ -59,7 +130,9 @@ func (f *Fn) ResetDelay(d time.Duration) error {
 	f.m.Lock()
 	defer f.m.Unlock()
 
-	f.cancel()
+	if f.closed {
+		return ErrClosed
+	}
 
 	debug("scheduled to run after %v", d)
 	f.d = d
@@ -71,10 +144,29 @@ func (f *Fn) Reset(d time.Duration, fn func()) error {
 	f.m.Lock()
 	defer f.m.Unlock()
 
-	f.cancel()
+	if f.closed {
+		return ErrClosed
+	}
+
 	debug("scheduled to run after %v", f.d)
 	f.d = d
-	f.fn = fn
+	f.fn = plain(fn)
+	return f.call()
+}
+
+// ResetContext binds f to ctx and resets both duration and the fn to call.
+// Later calls to Reset, ResetDelay and ResetFunc keep using ctx.
+func (f *Fn) ResetContext(ctx context.Context, d time.Duration, fn func(context.Context)) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	f.bind(ctx)
+	f.d = d
+	f.fn = contextual(fn)
 	return f.call()
 }
 
@@ -89,32 +181,334 @@ func (f *Fn) Call() error {
 func (f *Fn) Cancel() bool {
 	f.m.Lock()
 	defer f.m.Unlock()
-	return f.cancel()
+
+	cancelled := f.cancel()
+	f.settle()
+	return cancelled
 }
 
-func (f *Fn) call() error {
+// Close cancels the pending run and disables f for good: Call and every
+// Reset return ErrClosed afterwards. Calling Close again returns ErrClosed.
+func (f *Fn) Close() error {
+	f.m.Lock()
+	defer f.m.Unlock()
 
-	if !f.valid() {
-		return fmt.Errorf("invalid delayed function")
+	if f.closed {
+		return ErrClosed
 	}
 
+	debug("closing delayed call")
+	f.closed = true
 	f.cancel()
+	if f.unbind != nil {
+		f.unbind()
+	}
+	f.settle()
+	return nil
+}
+
+// callUnlessPending schedules a run only if none is pending or paused
+func (f *Fn) callUnlessPending() error {
+	f.m.Lock()
+	defer f.m.Unlock()
 
-	debug("Scheduled to run after %v", f.d)
-	f.t = time.AfterFunc(f.d, f.fn)
+	if f.pending || f.paused {
+		return nil
+	}
+	return f.call()
+}
+
+func (f *Fn) call() error {
+	if err := f.check(); err != nil {
+		return err
+	}
+
+	f.schedule(f.jittered(f.d))
 	return nil
 }
 
+// schedule supersedes the pending run with a new one due after d
+func (f *Fn) schedule(d time.Duration) {
+	f.scheduleAt(f.now().Add(d))
+}
+
+// scheduleAt supersedes the pending run with a new one due at deadline
+func (f *Fn) scheduleAt(deadline time.Time) {
+	d := nonNegative(deadline.Sub(f.now()))
+	debug("Scheduled to run after %v", d)
+	run := f.prepare(deadline)
+
+	// without an executor the timer's own goroutine runs the function
+	ex := f.executor()
+	if ex == nil {
+		ex = Inline
+	}
+	f.t = f.clk().AfterFunc(d, func() { ex.Execute(func() { run() }) })
+}
+
+// check returns why f cannot be scheduled, discarding the pending run if so
+func (f *Fn) check() error {
+	if f.closed {
+		return ErrClosed
+	}
+
+	err := f.invalid()
+	if err == nil {
+		err = f.context().Err()
+	}
+
+	if err != nil {
+		f.discard()
+	}
+	return err
+}
+
+// discard cancels the pending run that cannot be replaced by a new one
+func (f *Fn) discard() {
+	if f.supersede() {
+		f.observer().OnCancel()
+	}
+	f.settle()
+}
+
+// prepare supersedes the pending run with a new one due at deadline and
+// returns the func that performs it
+func (f *Fn) prepare(deadline time.Time) func() error {
+	previous, _ := f.due()
+	if f.supersede() {
+		f.observer().OnReschedule(previous, deadline)
+	} else {
+		f.observer().OnSchedule(deadline)
+	}
+
+	ctx, stop := context.WithCancel(f.context())
+	fn, policy := f.fn, f.panicPolicy()
+	f.gen++
+	gen := f.gen
+	f.stop = stop
+	f.pending = true
+	f.cancelled = false
+	f.deadline = deadline
+	if f.done == nil {
+		f.done = make(chan struct{})
+	}
+
+	return func() error {
+		defer stop()
+		return f.exec(ctx, stop, gen, fn, policy)
+	}
+}
+
+// exec runs fn and records its outcome as the last result
+func (f *Fn) exec(ctx context.Context, stop context.CancelFunc, gen uint64, fn func(context.Context) error, policy PanicPolicy) error {
+	if f.overlap.serial() {
+		f.serial.Lock()
+		defer f.serial.Unlock()
+	}
+
+	f.m.Lock()
+	if gen == f.gen {
+		f.pending = false
+	}
+
+	if f.overlap == OverlapSkip && f.running > 0 {
+		debug("skipping since the previous call is still running")
+		f.settle()
+		f.m.Unlock()
+		return nil
+	}
+
+	f.running++
+	if f.inflight == nil {
+		f.inflight = map[uint64]context.CancelFunc{}
+	}
+	f.inflight[gen] = stop
+	f.fires++
+	f.lastFired = f.now()
+	r := Result{Started: f.lastFired}
+	f.observer().OnFire(r.Started)
+	runCtx, expire := f.budget(ctx)
+	f.m.Unlock()
+
+	r.Err = invoke(runCtx, fn, policy)
+	r.Finished = f.now()
+	expired := expire()
+	if expired && (r.Err == nil || errors.Is(r.Err, context.Canceled)) {
+		r.Err = ErrTimeout
+	}
+
+	f.m.Lock()
+	f.running--
+	delete(f.inflight, gen)
+	f.last = &r
+	f.observer().OnComplete(r)
+	onError := f.onError
+
+	// reschedule unless the run was superseded or cancelled meanwhile
+	if _, panicked := r.Err.(*PanicError); panicked && policy == PanicReschedule && gen == f.gen && ctx.Err() == nil {
+		debug("rescheduling after panic: %v", r.Err)
+		f.call()
+	}
+	f.settle()
+	f.m.Unlock()
+
+	// an expired budget is reported as soon as it expires
+	if r.Err != nil && onError != nil && !(expired && r.Err == ErrTimeout) {
+		onError(r.Err)
+	}
+	return r.Err
+}
+
+// cancel stops the pending run and cancels the context of the latest run and
+// of every run in progress
 func (f *Fn) cancel() bool {
+	// a run started by Flush has a context but no timer
+	if f.stop != nil {
+		f.stop()
+	}
+	for _, stop := range f.inflight {
+		stop()
+	}
+
 	if f.t == nil {
 		return false
 	}
 
 	debug("cancelling delayed call")
-	return f.t.Stop()
+	if !f.stopTimer() {
+		return false
+	}
+
+	f.observer().OnCancel()
+	return true
+}
 
+// supersede stops the pending run to make way for a new one and returns
+// whether it did so. The context of a run that has already started is
+// cancelled only if the overlap policy lets the new run supersede it.
+func (f *Fn) supersede() bool {
+	stopped := f.t != nil && f.stopTimer()
+	if f.stop != nil && (stopped || f.overlap.supersedes()) {
+		f.stop()
+	}
+	return stopped
+}
+
+// hold stops the timer of the pending run before it fires so that it can be
+// scheduled again; false is returned if nothing is pending or the timer is
+// already firing
+func (f *Fn) hold() bool {
+	if !f.pending || f.t == nil || !f.t.Stop() {
+		return false
+	}
+
+	f.stop()
+	f.held = true
+	return true
+}
+
+func (f *Fn) stopTimer() bool {
+	if f.paused || f.held {
+		f.paused = false
+		f.held = false
+	} else if !f.t.Stop() {
+		return false
+	}
+
+	f.pending = false
+	f.cancelled = true
+	return true
+}
+
+// settle releases the waiters once nothing is pending or running
+func (f *Fn) settle() {
+	if f.done == nil || f.pending || f.paused || f.running > 0 {
+		return
+	}
+
+	close(f.done)
+	f.done = nil
+}
+
+// bind makes ctx the parent of all runs, cancelling f as soon as ctx is done
+func (f *Fn) bind(ctx context.Context) {
+	if f.unbind != nil {
+		f.unbind()
+	}
+
+	f.ctx = ctx
+	f.unbind = context.AfterFunc(ctx, func() {
+		debug("context done: %v", ctx.Err())
+		f.Cancel()
+	})
+}
+
+func (f *Fn) context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
+}
+
+func (f *Fn) clk() clock.Clock {
+	if f.clock == nil {
+		return clock.New()
+	}
+	return f.clock
+}
+
+func (f *Fn) now() time.Time {
+	return f.clk().Now()
 }
 
 func (f *Fn) valid() bool {
-	return f.fn != nil && f.d >= 0
+	return f.invalid() == nil
+}
+
+// invalid returns why f cannot be scheduled regardless of its state
+func (f *Fn) invalid() error {
+	switch {
+	case f.fn == nil:
+		return ErrNilFunc
+	case f.d < 0:
+		return ErrNegativeDelay
+	}
+	return nil
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// plain adapts fn to the form used to schedule runs
+func plain(fn func()) func(context.Context) error {
+	if fn == nil {
+		return nil
+	}
+	return func(context.Context) error {
+		fn()
+		return nil
+	}
+}
+
+// plainE adapts fn to the form used to schedule runs
+func plainE(fn func() error) func(context.Context) error {
+	if fn == nil {
+		return nil
+	}
+	return func(context.Context) error { return fn() }
+}
+
+// contextual adapts fn to the form used to schedule runs
+func contextual(fn func(context.Context)) func(context.Context) error {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	}
 }
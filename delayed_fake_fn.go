@@ -3,76 +3,30 @@ package delayed
 import (
 	"time"
 
-	"github.com/jonboulle/clockwork"
-	"github.com/sthaha/delayed/testutils"
+	"github.com/sthaha/delayed/clock"
 )
 
-// FakeFn satisfies a Callable where the clock can be controlled/mocked
+// FakeFn is a Fn where the clock can be controlled/mocked
 type FakeFn struct {
-	Clock clockwork.FakeClock
-
-	d  time.Duration
-	fn func()
-
-	cancel chan bool
+	*Fn
+	Clock *clock.Fake
 }
 
-func NewFakeFn(d time.Duration, fn func()) *FakeFn {
+// NewFakeFn returns a Fn that runs only when its Clock is advanced
+func NewFakeFn(d time.Duration, fn func(), opts ...Option) *FakeFn {
+	c := clock.NewFakeAt(time.Now())
+	opts = append(opts, WithClock(c))
+
 	return &FakeFn{
-		Clock:  clockwork.NewFakeClockAt(time.Now()),
-		d:      d,
-		fn:     fn,
-		cancel: make(chan bool),
+		Fn:    NewFn(d, fn, opts...),
+		Clock: c,
 	}
 }
 
-func FakeCall(d time.Duration, fn func()) *FakeFn {
-	f := NewFakeFn(d, fn)
+// FakeCall schedules fn to run once the Clock of the returned FakeFn is
+// advanced by d
+func FakeCall(d time.Duration, fn func(), opts ...Option) *FakeFn {
+	f := NewFakeFn(d, fn, opts...)
 	f.Call()
 	return f
 }
-
-func (m *FakeFn) Call() error {
-	log := testutils.Logger("call")
-
-	go func() {
-		log("waiting ....... %v to run func\n", m.d)
-		select {
-		case <-m.Clock.After(m.d):
-			log("running .......")
-			m.fn()
-			log("running ....... done")
-		case <-m.cancel:
-			log("got cancel .......")
-			return
-		}
-		log("waiting ....... done")
-	}()
-	time.Sleep(10 * time.Millisecond)
-	return nil
-}
-
-func (m *FakeFn) Cancel() bool {
-	select {
-	case m.cancel <- true:
-		return true
-	default:
-		return false
-	}
-}
-
-func (m *FakeFn) Reset(d time.Duration, fn func()) error {
-	m.Cancel()
-	m.d = d
-	m.fn = fn
-	m.Call()
-	return nil
-}
-
-func (m *FakeFn) ResetDelay(d time.Duration) error {
-	return m.Reset(d, m.fn)
-}
-
-func (m *FakeFn) ResetFunc(fn func()) error {
-	return m.Reset(m.d, fn)
-}
@@ -1,6 +1,7 @@
 package delayed_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -127,3 +128,49 @@ func TestFake_resetDelay(t *testing.T) {
 	assert.Equal(t, 1, c.Called)
 	assert.Equal(t, 1, c.Arg["v"])
 }
+
+func TestFake_remaining(t *testing.T) {
+	fn := delayed.FakeCall(2000*time.Millisecond, func() {})
+
+	fn.Clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, 1500*time.Millisecond, fn.Remaining())
+	assert.Equal(t, fn.Clock.Now().Add(1500*time.Millisecond), fn.Deadline())
+
+	fn.Clock.Advance(1500 * time.Millisecond)
+	fn.Wait(context.Background())
+	assert.Equal(t, delayed.StateFired, fn.State())
+	assert.Equal(t, fn.Clock.Now(), fn.LastFired())
+}
+
+func TestFake_pause(t *testing.T) {
+	spy := &testutils.Spy{}
+	fn := delayed.FakeCall(2000*time.Millisecond, spy.Hook(testutils.Args{"v": 1}))
+
+	fn.Clock.Advance(500 * time.Millisecond)
+	assert.True(t, fn.Pause())
+
+	fn.Clock.Advance(5000 * time.Millisecond)
+	assert.Equal(t, 1500*time.Millisecond, fn.Remaining())
+	assert.Equal(t, 0, spy.Called(), "must not run while paused")
+
+	fn.Resume()
+	fn.Clock.Advance(1499 * time.Millisecond)
+	assert.Equal(t, delayed.StatePending, fn.State())
+
+	fn.Clock.Advance(1 * time.Millisecond)
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, spy.Called())
+}
+
+func TestFake_pending_timers(t *testing.T) {
+	fn := delayed.FakeCall(2000*time.Millisecond, func() {})
+	now := fn.Clock.Now()
+	assert.Equal(t, []time.Time{now.Add(2000 * time.Millisecond)}, fn.Clock.Pending())
+
+	fn.ResetDelay(500 * time.Millisecond)
+	assert.Equal(t, []time.Time{now.Add(500 * time.Millisecond)}, fn.Clock.Pending(),
+		"reset must replace the pending timer")
+
+	fn.Cancel()
+	assert.Empty(t, fn.Clock.Pending())
+}
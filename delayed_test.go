@@ -1,6 +1,7 @@
 package delayed
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -149,3 +150,58 @@ func Test_cancel_calls(t *testing.T) {
 	assert.Equal(t, 1, s.Called(), "must be called once")
 	assert.Equal(t, 3, s.Args()["version"])
 }
+
+func TestCallContext(t *testing.T) {
+	s := &testutils.Spy{}
+	hook := s.Hook(testutils.Args{"version": 1})
+
+	_, err := CallContext(context.Background(), 20*time.Millisecond, func(context.Context) { hook() })
+	assert.NoError(t, err, "must be created")
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, s.Called(), "must be called once")
+}
+
+func TestCallContext_cancelled_parent(t *testing.T) {
+	s := &testutils.Spy{}
+	hook := s.Hook(testutils.Args{"version": 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fn, err := CallContext(ctx, 50*time.Millisecond, func(context.Context) { hook() })
+	assert.NoError(t, err, "must be created")
+
+	cancel()
+	time.Sleep(80 * time.Millisecond)
+	assert.Equal(t, 0, s.Called(), "must not be called after ctx is done")
+	assert.False(t, fn.Cancel(), "ctx must have cancelled the call")
+
+	assert.Error(t, fn.ResetDelay(10*time.Millisecond), "done ctx must not be rescheduled")
+	_, err = CallContext(ctx, 10*time.Millisecond, func(context.Context) {})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestResetContext_superseded_run(t *testing.T) {
+	started := make(chan struct{})
+	stopped := make(chan error)
+
+	fn := &Fn{}
+	fn.ResetContext(context.Background(), 10*time.Millisecond, func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		stopped <- ctx.Err()
+	})
+	<-started
+
+	s := &testutils.Spy{}
+	fn.Reset(10*time.Millisecond, s.Hook(testutils.Args{"version": 2}))
+
+	select {
+	case err := <-stopped:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("running fn was not told to stop")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, s.Called(), "must be called once")
+}
@@ -0,0 +1,28 @@
+package delayed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNilFunc is returned when scheduling a Fn without a func
+	ErrNilFunc = errors.New("delayed function is nil")
+
+	// ErrNegativeDelay is returned when scheduling a Fn with a negative delay
+	ErrNegativeDelay = errors.New("delay of delayed function is negative")
+
+	// ErrClosed is returned when using a Fn after Close
+	ErrClosed = errors.New("delayed function is closed")
+
+	// ErrCancelled is returned to waiters of a Future that was cancelled
+	ErrCancelled = errors.New("delayed call cancelled")
+
+	// ErrFutureDone is returned when rescheduling a Future that already ran
+	ErrFutureDone = errors.New("future already running or done")
+
+	// ErrTimeout is the error of a run that exceeded the timeout set using
+	// WithTimeout
+	ErrTimeout = fmt.Errorf("delayed function timed out: %w", context.DeadlineExceeded)
+)
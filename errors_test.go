@@ -0,0 +1,81 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	_, err := Call(-1*time.Millisecond, func() {})
+	assert.Equal(t, ErrNegativeDelay, err)
+
+	var invalidFn func()
+	_, err = Call(time.Millisecond, invalidFn)
+	assert.Equal(t, ErrNilFunc, err)
+
+	fn := &Fn{}
+	assert.Equal(t, ErrNilFunc, fn.Call())
+	assert.Equal(t, ErrNegativeDelay, fn.Reset(-1, func() {}))
+	assert.Equal(t, ErrNilFunc, fn.ReplaceFunc(invalidFn))
+}
+
+func TestClose(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(20*time.Millisecond, s.Hook(testutils.Args{"version": 1}))
+
+	assert.NoError(t, fn.Close())
+	assert.Equal(t, ErrClosed, fn.Close(), "must not be closed twice")
+	assert.Equal(t, StateCancelled, fn.State())
+
+	assert.Equal(t, ErrClosed, fn.Call())
+	assert.Equal(t, ErrClosed, fn.Reset(time.Millisecond, s.Hook(testutils.Args{"version": 2})))
+	assert.Equal(t, ErrClosed, fn.ResetDelay(time.Millisecond))
+	assert.Equal(t, ErrClosed, fn.ResetFunc(func() {}))
+	assert.Equal(t, ErrClosed, fn.ResetE(time.Millisecond, func() error { return nil }))
+	assert.Equal(t, ErrClosed, fn.ResetContext(context.Background(), time.Millisecond, func(context.Context) {}))
+	assert.Equal(t, ErrClosed, fn.ResetAt(time.Now()))
+	assert.Equal(t, ErrClosed, fn.ReplaceFunc(func() {}))
+	assert.Equal(t, ErrClosed, fn.Flush())
+	assert.False(t, fn.Cancel())
+
+	_, err := fn.ResetIfEarlier(time.Millisecond)
+	assert.Equal(t, ErrClosed, err)
+
+	// a closed fn is left untouched
+	fn.Reset(time.Hour, nil)
+	fn.ResetFuncE(nil)
+	fn.ResetAt(time.Now().Add(time.Hour))
+	assert.Equal(t, 20*time.Millisecond, fn.d)
+	assert.NotNil(t, fn.fn)
+
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, 0, s.Called(), "closed fn must never run")
+}
+
+func TestClose_stray_goroutines(t *testing.T) {
+	s := &testutils.Spy{}
+	fn := &Fn{}
+
+	wg := &sync.WaitGroup{}
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			time.Sleep(time.Duration(i) * time.Millisecond)
+			fn.Reset(10*time.Millisecond, s.Hook(testutils.Args{"version": i}))
+		}(i)
+	}
+
+	time.Sleep(3 * time.Millisecond)
+	fn.Close()
+	wg.Wait()
+
+	fn.Wait(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 0, s.Called(), "nothing must run after close")
+}
@@ -1,6 +1,7 @@
 package delayed_test
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -48,7 +49,7 @@ func Example_long_running_fn() {
 	fmt.Printf("not mutex protected thus count is %d\n", called)
 
 	// wait for both to finish
-	time.Sleep(800 * time.Millisecond)
+	fn.Wait(context.Background())
 
 	called = atomic.LoadUint64(&count)
 	fmt.Printf("expect calls to be %d\n", called)
@@ -102,13 +103,60 @@ func Example_long_running_fn_fixed() {
 	fmt.Printf("mutex protected thus count is %d\n", called)
 
 	// wait for both to finish
-	time.Sleep(800 * time.Millisecond)
+	fn.Wait(context.Background())
 
 	called = atomic.LoadUint64(&count)
 	fmt.Printf("expect calls to be %d\n", called)
-	time.Sleep(800 * time.Millisecond)
 
 	// Output:
 	// mutex protected thus count is 1
 	// expect calls to be 2
 }
+
+func Example_long_running_fn_serialized() {
+	debug := testutils.Logger("main")
+
+	var count uint64
+
+	fn := delayed.NewFn(100*time.Millisecond, func() {
+		debug := testutils.Logger("first")
+
+		debug("going to execute")
+		atomic.AddUint64(&count, 1)
+		// simulate some activity while a Reset could be called from
+		// else where in the code
+		time.Sleep(500 * time.Millisecond)
+
+		debug("execute done")
+	}, delayed.WithOverlap(delayed.OverlapSerialize))
+	fn.Call()
+
+	// give enough time for the call to run
+	time.Sleep(110 * time.Millisecond)
+	debug("going to schedule another")
+
+	fn.Reset(100*time.Millisecond, func() {
+		debug := testutils.Logger("second")
+
+		debug("going to execute")
+		atomic.AddUint64(&count, 1)
+
+		time.Sleep(200 * time.Millisecond)
+		debug("execute done")
+	})
+
+	time.Sleep(110 * time.Millisecond)
+
+	called := atomic.LoadUint64(&count)
+	fmt.Printf("serialized thus count is %d\n", called)
+
+	// wait for both to finish
+	fn.Wait(context.Background())
+
+	called = atomic.LoadUint64(&count)
+	fmt.Printf("expect calls to be %d\n", called)
+
+	// Output:
+	// serialized thus count is 1
+	// expect calls to be 2
+}
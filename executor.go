@@ -0,0 +1,124 @@
+package delayed
+
+import (
+	"sync"
+)
+
+// Executor runs the function of a Fn once it is due
+type Executor interface {
+	Execute(fn func())
+}
+
+// ExecutorFunc adapts a func to an Executor
+type ExecutorFunc func(fn func())
+
+// Execute calls e(fn)
+func (e ExecutorFunc) Execute(fn func()) {
+	e(fn)
+}
+
+// Inline runs functions on the goroutine that executes them: the goroutine
+// of the timer when a run is due, or the caller of Flush.
+var Inline Executor = ExecutorFunc(func(fn func()) { fn() })
+
+var (
+	executorMu      sync.RWMutex
+	defaultExecutor Executor
+)
+
+// SetExecutor sets the Executor of every Fn that is not configured using
+// WithExecutor. If it is never set, every run gets its own goroutine.
+func SetExecutor(e Executor) {
+	executorMu.Lock()
+	defer executorMu.Unlock()
+	defaultExecutor = e
+}
+
+// WithExecutor makes Fn run its function using e
+func WithExecutor(e Executor) Option {
+	return func(f *Fn) { f.ex = e }
+}
+
+// Pool is an Executor that runs functions on a fixed number of goroutines.
+// Functions wait in a queue for a free worker so Execute never blocks.
+type Pool struct {
+	m      sync.Mutex
+	ready  *sync.Cond
+	queue  []func()
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewPool starts workers goroutines
+func NewPool(workers int) *Pool {
+	p := &Pool{}
+	p.ready = sync.NewCond(&p.m)
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Execute queues fn to be run by a worker; once the Pool is closed fn runs
+// on the caller instead
+func (p *Pool) Execute(fn func()) {
+	p.m.Lock()
+	if p.closed {
+		p.m.Unlock()
+		debug("pool closed, running function inline")
+		fn()
+		return
+	}
+
+	p.queue = append(p.queue, fn)
+	p.ready.Signal()
+	p.m.Unlock()
+}
+
+// Close waits for the queued functions to run and stops the workers. Runs
+// handed to the Pool afterwards are performed by Inline.
+func (p *Pool) Close() {
+	p.m.Lock()
+	p.closed = true
+	p.ready.Broadcast()
+	p.m.Unlock()
+
+	p.wg.Wait()
+}
+
+// work runs queued functions until the Pool is closed and drained
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for {
+		p.m.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.ready.Wait()
+		}
+
+		if len(p.queue) == 0 {
+			p.m.Unlock()
+			return
+		}
+
+		fn := p.queue[0]
+		p.queue[0] = nil
+		p.queue = p.queue[1:]
+		p.m.Unlock()
+
+		fn()
+	}
+}
+
+// executor returns the Executor of f; nil means a goroutine per run
+func (f *Fn) executor() Executor {
+	if f.ex != nil {
+		return f.ex
+	}
+
+	executorMu.RLock()
+	defer executorMu.RUnlock()
+	return defaultExecutor
+}
@@ -0,0 +1,172 @@
+package delayed
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+	"github.com/sthaha/delayed/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExecutor(t *testing.T) {
+	var executed int32
+	ex := ExecutorFunc(func(fn func()) {
+		atomic.AddInt32(&executed, 1)
+		fn()
+	})
+
+	s := &testutils.Spy{}
+	fn, _ := Call(10*time.Millisecond, s.Hook(testutils.Args{"version": 1}), WithExecutor(ex))
+	fn.Wait(context.Background())
+
+	assert.Equal(t, 1, s.Called(), "must be called once")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&executed), "must run using the executor")
+}
+
+func TestSetExecutor(t *testing.T) {
+	var executed int32
+	SetExecutor(ExecutorFunc(func(fn func()) {
+		atomic.AddInt32(&executed, 1)
+		go fn()
+	}))
+	defer SetExecutor(nil)
+
+	fn, _ := Call(time.Millisecond, func() {})
+	fn.Wait(context.Background())
+
+	fn = NewFn(time.Millisecond, func() {}, WithExecutor(Inline))
+	fn.Call()
+	fn.Wait(context.Background())
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&executed), "option must override the default")
+}
+
+func TestInline_async_flush(t *testing.T) {
+	s := &testutils.Spy{}
+	fn := NewFn(time.Second, s.Hook(testutils.Args{"version": 1}), WithAsyncFlush(), WithExecutor(Inline))
+	fn.Call()
+
+	fn.Flush()
+	assert.Equal(t, 1, s.Called(), "inline executor must run on the caller")
+}
+
+func TestPool(t *testing.T) {
+	const workers = 4
+
+	c := clock.NewFake()
+	pool := NewPool(workers)
+
+	var active, peak int32
+	release := make(chan struct{})
+	wg := &sync.WaitGroup{}
+
+	fns := make([]*Fn, 100)
+	for i := range fns {
+		wg.Add(1)
+		fns[i] = NewFn(time.Second, func() {
+			defer wg.Done()
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&active, -1)
+		}, WithClock(c), WithExecutor(pool))
+		fns[i].Call()
+	}
+
+	c.Advance(time.Second)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(workers), atomic.LoadInt32(&active), "pool must bound the concurrent runs")
+
+	close(release)
+	wg.Wait()
+	pool.Close()
+
+	assert.Equal(t, int32(workers), atomic.LoadInt32(&peak))
+	for _, fn := range fns {
+		fn.Wait(context.Background())
+		assert.Equal(t, StateFired, fn.State())
+	}
+}
+
+func TestPool_goroutines(t *testing.T) {
+	const workers = 4
+
+	c := clock.NewFake()
+	pool := NewPool(workers)
+	release := make(chan struct{})
+	before := runtime.NumGoroutine()
+
+	fns := make([]*Fn, 1000)
+	for i := range fns {
+		fns[i] = NewFn(time.Second, func() { <-release }, WithClock(c), WithExecutor(pool))
+		fns[i].Call()
+	}
+	c.Advance(time.Second)
+
+	// the timers hand their runs off to the pool without waiting for a worker
+	extra := 0
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if extra = runtime.NumGoroutine() - before; extra <= workers {
+			break
+		}
+	}
+	assert.True(t, extra <= workers, "queued runs must not hold goroutines, got %d extra", extra)
+
+	close(release)
+	pool.Close()
+	for _, fn := range fns {
+		fn.Wait(context.Background())
+		assert.Equal(t, StateFired, fn.State())
+	}
+}
+
+func TestPool_close(t *testing.T) {
+	c := clock.NewFake()
+	pool := NewPool(1)
+	release := make(chan struct{})
+
+	var calls int32
+	fns := make([]*Fn, 10)
+	for i := range fns {
+		fns[i] = NewFn(time.Second, func() {
+			<-release
+			atomic.AddInt32(&calls, 1)
+		}, WithClock(c), WithExecutor(pool))
+		fns[i].Call()
+	}
+	c.Advance(time.Second)
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(closed)
+	}()
+	close(release)
+	<-closed
+	assert.Equal(t, int32(10), atomic.LoadInt32(&calls), "close must run the queued functions")
+	for _, fn := range fns {
+		assert.Equal(t, StateFired, fn.State())
+	}
+
+	// runs due after close are performed inline
+	fn := NewFn(time.Second, func() { atomic.AddInt32(&calls, 1) }, WithClock(c), WithExecutor(pool))
+	fn.Call()
+	c.Advance(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, fn.Wait(ctx), "wait must return once the run is done")
+	assert.Equal(t, int32(11), atomic.LoadInt32(&calls))
+	assert.Equal(t, StateFired, fn.State())
+}
@@ -0,0 +1,64 @@
+package delayed
+
+// Flush cancels the pending or paused run and calls the function right away;
+// it does nothing if no run is pending or paused. Unless f was created using
+// WithAsyncFlush, Flush waits for the function to complete and returns its
+// error; otherwise the function is run using the Executor of f.
+func (f *Fn) Flush() error {
+	_, err := f.flush(true)
+	return err
+}
+
+// FlushIfPending is Flush reporting whether a run was pending or paused
+// instead of the error of the function
+func (f *Fn) FlushIfPending() bool {
+	flushed, _ := f.flush(true)
+	return flushed
+}
+
+// callNow calls the function right away whether or not a run is pending, as
+// Flush does otherwise
+func (f *Fn) callNow() error {
+	_, err := f.flush(false)
+	return err
+}
+
+// flush calls the function right away unless pendingOnly is set and no run
+// is pending or paused; returns whether it did so
+func (f *Fn) flush(pendingOnly bool) (bool, error) {
+	f.m.Lock()
+	if f.closed {
+		f.m.Unlock()
+		return false, ErrClosed
+	}
+
+	if pendingOnly && !f.paused && !f.hold() {
+		f.m.Unlock()
+		return false, nil
+	}
+
+	if err := f.check(); err != nil {
+		f.m.Unlock()
+		return false, err
+	}
+
+	debug("flushing delayed call")
+	run := f.prepare(f.now())
+	async, ex := f.asyncFlush, f.executor()
+	f.m.Unlock()
+
+	if async {
+		launch(run, ex)
+		return true, nil
+	}
+	return true, run()
+}
+
+// launch performs run using ex, or in its own goroutine if ex is nil
+func launch(run func() error, ex Executor) {
+	if ex == nil {
+		go run()
+		return
+	}
+	ex.Execute(func() { run() })
+}
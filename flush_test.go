@@ -0,0 +1,78 @@
+package delayed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlush(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(time.Second, s.Hook(testutils.Args{"version": 1}))
+
+	assert.NoError(t, fn.Flush())
+	assert.Equal(t, 1, s.Called(), "flush must run the func synchronously")
+	assert.Equal(t, StateFired, fn.State(), "flush must clear the timer")
+
+	// nothing is pending once the func has run
+	assert.NoError(t, fn.Flush())
+	assert.Equal(t, 1, s.Called(), "flush must not run the func again")
+}
+
+func TestFlush_error(t *testing.T) {
+	failed := errors.New("failed")
+	fn, _ := CallE(time.Second, func() error { return failed })
+	assert.Equal(t, failed, fn.Flush())
+
+	assert.NoError(t, (&Fn{}).Flush(), "flushing with nothing pending must do nothing")
+}
+
+func TestFlush_async(t *testing.T) {
+	s := &testutils.Spy{}
+	release := make(chan struct{})
+	hook := s.Hook(testutils.Args{"version": 1})
+
+	fn := NewFn(time.Second, func() {
+		<-release
+		hook()
+	}, WithAsyncFlush())
+	fn.Call()
+
+	assert.NoError(t, fn.Flush(), "async flush must not wait for the func")
+	assert.Equal(t, 0, s.Called())
+
+	close(release)
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called())
+}
+
+func TestFlushIfPending(t *testing.T) {
+	s := &testutils.Spy{}
+	fn := &Fn{}
+	assert.False(t, fn.FlushIfPending(), "nothing is pending")
+
+	fn.Reset(time.Second, s.Hook(testutils.Args{"version": 1}))
+	assert.True(t, fn.FlushIfPending())
+	assert.Equal(t, 1, s.Called())
+
+	assert.False(t, fn.FlushIfPending(), "nothing is pending after flush")
+
+	fn.ResetDelay(time.Second)
+	fn.Cancel()
+	assert.False(t, fn.FlushIfPending(), "cancelled call must not be flushed")
+	assert.Equal(t, 1, s.Called())
+}
+
+func TestFlush_paused(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(time.Second, s.Hook(testutils.Args{"version": 1}))
+	fn.Pause()
+
+	assert.NoError(t, fn.Flush())
+	assert.Equal(t, 1, s.Called(), "paused call must be flushed")
+	assert.Equal(t, StateFired, fn.State())
+}
@@ -0,0 +1,147 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Future is the value of a function that is called after some duration has
+// elapsed
+type Future[T any] struct {
+	fn *Fn
+
+	// retrying is set while a run that panicked waits to be retried
+	m        sync.Mutex
+	started  bool
+	retrying bool
+	done     chan struct{}
+	v        T
+	err      error
+}
+
+// After calls fn after the duration and returns a Future holding its result
+func After[T any](d time.Duration, fn func() (T, error), opts ...Option) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+
+	var run func(context.Context) error
+	if fn != nil {
+		run = func(ctx context.Context) error {
+			if !f.start() {
+				return nil
+			}
+
+			// a recovered panic completes the future unless the run is
+			// retried
+			var v T
+			policy := f.fn.panicPolicy()
+			err := invoke(ctx, func(context.Context) (err error) {
+				v, err = fn()
+				return err
+			}, policy)
+
+			if _, panicked := err.(*PanicError); panicked && policy == PanicReschedule {
+				f.restart()
+				return err
+			}
+
+			f.complete(v, err)
+			return err
+		}
+	}
+
+	f.fn = newFn(d, run, opts)
+	if err := f.fn.Call(); err != nil {
+		var zero T
+		f.complete(zero, err)
+	}
+	return f
+}
+
+// Get waits for the result of the Future unless ctx is done first
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.v, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed once the result is available
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Cancel prevents the function from being called, waiters receive
+// ErrCancelled. Returns false if the function is already running or done.
+func (f *Future[T]) Cancel() bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if !f.cancel() {
+		return false
+	}
+
+	f.started = true
+	var zero T
+	f.complete(zero, ErrCancelled)
+	return true
+}
+
+// Reschedule calls the function after d instead of the original duration
+func (f *Future[T]) Reschedule(d time.Duration) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if !f.cancel() {
+		return ErrFutureDone
+	}
+
+	if err := f.fn.ResetDelay(d); err != nil {
+		f.started = true
+		var zero T
+		f.complete(zero, err)
+		return err
+	}
+	return nil
+}
+
+// start marks the function as started; false is returned if the Future was
+// cancelled or the run was superseded
+func (f *Future[T]) start() bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.started {
+		return false
+	}
+	f.started = true
+	f.retrying = false
+	return true
+}
+
+// restart lets the function be started again by a rescheduled run
+func (f *Future[T]) restart() {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.started = false
+	f.retrying = true
+}
+
+// cancel cancels the pending run and returns whether the function is not
+// about to start. A run that panicked is not retried once its context is
+// cancelled, so a retry counts as cancelled even before it is rescheduled.
+func (f *Future[T]) cancel() bool {
+	if f.started {
+		return false
+	}
+	return f.fn.Cancel() || f.retrying
+}
+
+// complete must be called only once
+func (f *Future[T]) complete(v T, err error) {
+	f.v, f.err = v, err
+	close(f.done)
+}
@@ -0,0 +1,119 @@
+package delayed_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfter(t *testing.T) {
+	f := delayed.After(10*time.Millisecond, func() (int, error) {
+		return 42, nil
+	})
+
+	v, err := f.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+
+	select {
+	case <-f.Done():
+	default:
+		t.Fatal("done must be closed once the value is available")
+	}
+	assert.False(t, f.Cancel(), "cancelling a completed future must return false")
+	assert.Equal(t, delayed.ErrFutureDone, f.Reschedule(time.Millisecond))
+}
+
+func TestAfter_error(t *testing.T) {
+	failed := errors.New("failed")
+	f := delayed.After(10*time.Millisecond, func() (string, error) {
+		return "", failed
+	})
+
+	_, err := f.Get(context.Background())
+	assert.Equal(t, failed, err)
+
+	f = delayed.After(-1*time.Millisecond, func() (string, error) {
+		return "never", nil
+	})
+	_, err = f.Get(context.Background())
+	assert.Error(t, err, "invalid duration must fail the future")
+}
+
+func TestAfter_panic_report(t *testing.T) {
+	f := delayed.After(10*time.Millisecond, func() (int, error) {
+		panic("boom")
+	}, delayed.WithPanicPolicy(delayed.PanicReport))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := f.Get(ctx)
+	perr, ok := err.(*delayed.PanicError)
+	assert.True(t, ok, "a panic must complete the future with a *PanicError, got %v", err)
+	if ok {
+		assert.Equal(t, "boom", perr.Value)
+	}
+}
+
+func TestAfter_panic_reschedule(t *testing.T) {
+	attempts := 0
+	f := delayed.After(10*time.Millisecond, func() (int, error) {
+		attempts++
+		if attempts == 1 {
+			panic("boom")
+		}
+		return attempts, nil
+	}, delayed.WithPanicPolicy(delayed.PanicReschedule))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	v, err := f.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v, "the future must complete with the retried run")
+}
+
+func TestFuture_cancel(t *testing.T) {
+	called := make(chan struct{}, 1)
+	f := delayed.After(50*time.Millisecond, func() (int, error) {
+		called <- struct{}{}
+		return 1, nil
+	})
+
+	assert.True(t, f.Cancel(), "cancel on scheduled must return true")
+
+	_, err := f.Get(context.Background())
+	assert.Equal(t, delayed.ErrCancelled, err)
+
+	time.Sleep(80 * time.Millisecond)
+	assert.Len(t, called, 0, "must not be called")
+}
+
+func TestFuture_get_timeout(t *testing.T) {
+	f := delayed.After(time.Second, func() (int, error) { return 1, nil })
+	defer f.Cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := f.Get(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestFuture_reschedule(t *testing.T) {
+	start := time.Now()
+	f := delayed.After(time.Second, func() (time.Time, error) {
+		return time.Now(), nil
+	})
+
+	assert.NoError(t, f.Reschedule(10*time.Millisecond))
+
+	ranAt, err := f.Get(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ranAt.Sub(start) < time.Second, "must run after the new delay")
+}
@@ -0,0 +1,63 @@
+package delayed
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Jitter spreads a delay d using r, a random number in [0, 1)
+type Jitter func(d time.Duration, r float64) time.Duration
+
+// AbsoluteJitter moves the delay by a random duration in [-max, max)
+func AbsoluteJitter(max time.Duration) Jitter {
+	return func(d time.Duration, r float64) time.Duration {
+		return d + time.Duration((2*r-1)*float64(max))
+	}
+}
+
+// ProportionalJitter moves the delay by a random fraction in [-p, p) of it,
+// e.g. 0.1 spreads the delay by ±10%
+func ProportionalJitter(p float64) Jitter {
+	return func(d time.Duration, r float64) time.Duration {
+		return d + time.Duration((2*r-1)*p*float64(d))
+	}
+}
+
+// FullJitter picks a random delay in [0, d)
+func FullJitter() Jitter {
+	return func(d time.Duration, r float64) time.Duration {
+		return time.Duration(r * float64(d))
+	}
+}
+
+// EqualJitter picks a random delay in [d/2, d)
+func EqualJitter() Jitter {
+	return func(d time.Duration, r float64) time.Duration {
+		return d/2 + time.Duration(r*float64(d/2))
+	}
+}
+
+// WithJitter spreads the delay of every Call, Reset and ResetDelay using j
+// so that many Fn reset with the same delay do not fire at once
+func WithJitter(j Jitter) Option {
+	return func(f *Fn) { f.jitter = j }
+}
+
+// WithRandom makes the jitter use random numbers in [0, 1) returned by r
+// instead of math/rand
+func WithRandom(r func() float64) Option {
+	return func(f *Fn) { f.random = r }
+}
+
+// jittered returns d spread by the jitter of f
+func (f *Fn) jittered(d time.Duration) time.Duration {
+	if f.jitter == nil {
+		return d
+	}
+
+	random := f.random
+	if random == nil {
+		random = rand.Float64
+	}
+	return nonNegative(f.jitter(d, random()))
+}
@@ -0,0 +1,96 @@
+package delayed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJitter(t *testing.T) {
+	d := 10 * time.Second
+
+	assert.Equal(t, 8*time.Second, AbsoluteJitter(2*time.Second)(d, 0))
+	assert.Equal(t, 10*time.Second, AbsoluteJitter(2*time.Second)(d, 0.5))
+	assert.Equal(t, 11*time.Second, AbsoluteJitter(2*time.Second)(d, 0.75))
+
+	assert.Equal(t, 9*time.Second, ProportionalJitter(0.1)(d, 0))
+	assert.Equal(t, 10500*time.Millisecond, ProportionalJitter(0.1)(d, 0.75))
+
+	assert.Equal(t, time.Duration(0), FullJitter()(d, 0))
+	assert.Equal(t, 2500*time.Millisecond, FullJitter()(d, 0.25))
+
+	assert.Equal(t, 5*time.Second, EqualJitter()(d, 0))
+	assert.Equal(t, 7500*time.Millisecond, EqualJitter()(d, 0.5))
+}
+
+func TestWithJitter(t *testing.T) {
+	c := clock.NewFake()
+	r := 0.0
+
+	fn := NewFn(10*time.Second, func() {},
+		WithClock(c),
+		WithJitter(AbsoluteJitter(2*time.Second)),
+		WithRandom(func() float64 { return r }),
+	)
+
+	fn.Call()
+	assert.Equal(t, 8*time.Second, fn.Remaining(), "call must be jittered")
+
+	r = 0.75
+	fn.ResetDelay(20 * time.Second)
+	assert.Equal(t, 21*time.Second, fn.Remaining(), "reset delay must be jittered")
+
+	r = 0.5
+	fn.Reset(time.Second, func() {})
+	assert.Equal(t, time.Second, fn.Remaining(), "reset must be jittered")
+
+	// jitter never makes the delay negative
+	r = 0
+	fn.ResetDelay(time.Second)
+	assert.Equal(t, time.Duration(0), fn.Remaining())
+	fn.Cancel()
+}
+
+func TestWithJitter_reset_if_earlier(t *testing.T) {
+	c := clock.NewFake()
+	r := 0.0
+
+	fn := NewFn(10*time.Second, func() {},
+		WithClock(c),
+		WithJitter(AbsoluteJitter(2*time.Second)),
+		WithRandom(func() float64 { return r }),
+	)
+	fn.Call()
+	assert.Equal(t, 8*time.Second, fn.Remaining())
+
+	// 7.5s is earlier than 8s but jittered to 8.5s it is not
+	r = 0.75
+	ok, err := fn.ResetIfEarlier(7500 * time.Millisecond)
+	assert.NoError(t, err)
+	assert.False(t, ok, "jittered deadline must be compared")
+	assert.Equal(t, 8*time.Second, fn.Remaining())
+
+	r = 0
+	ok, _ = fn.ResetIfEarlier(7500 * time.Millisecond)
+	assert.True(t, ok)
+	assert.Equal(t, 5500*time.Millisecond, fn.Remaining(), "the compared deadline must be scheduled")
+	fn.Cancel()
+}
+
+func TestWithJitter_spread(t *testing.T) {
+	c := clock.NewFake()
+
+	deadlines := map[time.Time]bool{}
+	for i := 0; i < 10; i++ {
+		fn := NewFn(time.Minute, func() {}, WithClock(c), WithJitter(ProportionalJitter(0.5)))
+		fn.Call()
+
+		remaining := fn.Remaining()
+		assert.True(t, remaining >= 30*time.Second && remaining < 90*time.Second)
+		deadlines[fn.Deadline()] = true
+		fn.Cancel()
+	}
+	assert.True(t, len(deadlines) > 1, "deadlines must be spread")
+}
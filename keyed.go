@@ -0,0 +1,131 @@
+package delayed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+)
+
+// KeyedDebouncer debounces the triggers of every key on its own. The state
+// kept for a key is dropped once its func was called or its burst is over,
+// whichever is later.
+type KeyedDebouncer[K comparable] struct {
+	m    sync.Mutex
+	wait time.Duration
+	fn   func(K)
+	o    DebounceOptions
+	opts []Option
+
+	entries map[K]*entry
+}
+
+// entry is the Debouncer of a key; evict fires once the key may be idle and
+// users counts the triggers in progress
+type entry struct {
+	*Debouncer
+	evict clock.Timer
+	users int
+}
+
+// NewKeyedDebouncer returns a KeyedDebouncer that calls fn with a key once
+// per burst of triggers of that key that are less than wait apart. o and
+// opts configure the Debouncer of every key.
+func NewKeyedDebouncer[K comparable](wait time.Duration, fn func(K), o DebounceOptions, opts ...Option) *KeyedDebouncer[K] {
+	return &KeyedDebouncer[K]{
+		wait:    wait,
+		fn:      fn,
+		o:       o,
+		opts:    opts,
+		entries: map[K]*entry{},
+	}
+}
+
+// Trigger records an event for key; see Debouncer.Trigger
+func (k *KeyedDebouncer[K]) Trigger(key K) error {
+	e := k.acquire(key)
+	defer k.release(e)
+	return e.Trigger()
+}
+
+// Flush calls the func for key right away if a trailing call is pending;
+// returns whether it did so
+func (k *KeyedDebouncer[K]) Flush(key K) bool {
+	e, ok := k.lookup(key)
+	return ok && e.Flush()
+}
+
+// Cancel cancels the pending trailing call for key; returns false if nothing
+// is pending
+func (k *KeyedDebouncer[K]) Cancel(key K) bool {
+	e, ok := k.lookup(key)
+	return ok && e.Cancel()
+}
+
+// Len returns the number of keys state is kept for
+func (k *KeyedDebouncer[K]) Len() int {
+	k.m.Lock()
+	defer k.m.Unlock()
+	return len(k.entries)
+}
+
+func (k *KeyedDebouncer[K]) lookup(key K) (*entry, bool) {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	e, ok := k.entries[key]
+	return e, ok
+}
+
+// acquire returns the entry of key, creating it if needed, and keeps it from
+// being evicted until it is released
+func (k *KeyedDebouncer[K]) acquire(key K) *entry {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	e, ok := k.entries[key]
+	if !ok {
+		var call func()
+		if k.fn != nil {
+			call = func() {
+				k.fn(key)
+				k.evict(key, e)
+			}
+		}
+
+		e = &entry{Debouncer: NewDebouncer(k.wait, call, k.o, k.opts...)}
+		e.evict = e.fn.clk().AfterFunc(k.wait, func() { k.evict(key, e) })
+		k.entries[key] = e
+	}
+
+	e.users++
+	return e
+}
+
+// release makes e evictable once the wait has passed
+func (k *KeyedDebouncer[K]) release(e *entry) {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	e.users--
+	e.evict.Reset(k.wait)
+}
+
+// evict drops the entry of key unless it is in use or its burst goes on, in
+// which case it is checked again after the wait
+func (k *KeyedDebouncer[K]) evict(key K, e *entry) {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	if k.entries[key] != e || e.users > 0 {
+		return
+	}
+
+	if !e.idle() {
+		e.evict.Reset(k.wait)
+		return
+	}
+
+	debug("debounce: evicting %v", key)
+	delete(k.entries, key)
+}
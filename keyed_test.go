@@ -0,0 +1,93 @@
+package delayed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed"
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventually polls cond for up to a second and returns whether it held
+func eventually(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return cond()
+}
+
+func TestKeyedDebouncer(t *testing.T) {
+	c := clock.NewFake()
+	r := &recorder{}
+	k := delayed.NewKeyedDebouncer(time.Second, r.record, delayed.DebounceOptions{}, delayed.WithClock(c))
+
+	for i := 0; i < 3; i++ {
+		k.Trigger("a")
+		k.Trigger("b")
+		c.Advance(500 * time.Millisecond)
+	}
+	k.Trigger("a")
+	assert.Equal(t, 2, k.Len())
+	assert.Empty(t, r.recorded())
+
+	c.Advance(500 * time.Millisecond)
+	assert.True(t, eventually(func() bool { return len(r.recorded()) == 1 }))
+	assert.Equal(t, []string{"b"}, r.recorded(), "keys must be debounced on their own")
+
+	c.Advance(500 * time.Millisecond)
+	assert.True(t, eventually(func() bool { return k.Len() == 0 }),
+		"keys must be evicted once they fired")
+	assert.ElementsMatch(t, []string{"a", "b"}, r.recorded())
+}
+
+func TestKeyedDebouncer_leading(t *testing.T) {
+	c := clock.NewFake()
+	r := &recorder{}
+	k := delayed.NewKeyedDebouncer(time.Second, r.record,
+		delayed.DebounceOptions{Edge: delayed.LeadingEdge}, delayed.WithClock(c))
+
+	k.Trigger("a")
+	c.Advance(500 * time.Millisecond)
+	k.Trigger("a")
+	assert.Equal(t, []string{"a"}, r.recorded())
+
+	// the key is kept until its burst is over
+	c.Advance(500 * time.Millisecond)
+	k.Trigger("a")
+	assert.Equal(t, []string{"a"}, r.recorded())
+
+	c.Advance(time.Second)
+	assert.True(t, eventually(func() bool { return k.Len() == 0 }),
+		"idle keys must be evicted")
+
+	k.Trigger("a")
+	assert.Equal(t, []string{"a", "a"}, r.recorded())
+}
+
+func TestKeyedDebouncer_flush_and_cancel(t *testing.T) {
+	c := clock.NewFake()
+	r := &recorder{}
+	k := delayed.NewKeyedDebouncer(time.Second, r.record, delayed.DebounceOptions{}, delayed.WithClock(c))
+
+	assert.False(t, k.Flush("a"), "unknown key")
+	assert.False(t, k.Cancel("a"), "unknown key")
+
+	k.Trigger("a")
+	k.Trigger("b")
+	assert.True(t, k.Flush("a"))
+	assert.True(t, k.Cancel("b"))
+	assert.Equal(t, []string{"a"}, r.recorded())
+
+	c.Advance(time.Second)
+	assert.True(t, eventually(func() bool { return k.Len() == 0 }))
+	assert.Equal(t, []string{"a"}, r.recorded())
+}
+
+func TestKeyedDebouncer_nil_func(t *testing.T) {
+	k := delayed.NewKeyedDebouncer[string](time.Second, nil, delayed.DebounceOptions{})
+	assert.Equal(t, delayed.ErrNilFunc, k.Trigger("a"))
+}
@@ -0,0 +1,57 @@
+package delayed
+
+import (
+	"time"
+)
+
+// Observer is told about every change in the state of a Fn, e.g. to collect
+// metrics. Its methods are called while the Fn is locked, so they must
+// return quickly and must not call the Fn.
+type Observer interface {
+	// OnSchedule is called when a run is scheduled while nothing is pending
+	OnSchedule(deadline time.Time)
+
+	// OnReschedule is called when the pending run is replaced by one due at
+	// deadline
+	OnReschedule(previous, deadline time.Time)
+
+	// OnCancel is called when the pending run is cancelled
+	OnCancel()
+
+	// OnFire is called when a run starts
+	OnFire(started time.Time)
+
+	// OnComplete is called when a run completes
+	OnComplete(r Result)
+}
+
+// NopObserver ignores every change; embed it to implement only some of the
+// methods of Observer
+type NopObserver struct{}
+
+// OnSchedule does nothing
+func (NopObserver) OnSchedule(time.Time) {}
+
+// OnReschedule does nothing
+func (NopObserver) OnReschedule(time.Time, time.Time) {}
+
+// OnCancel does nothing
+func (NopObserver) OnCancel() {}
+
+// OnFire does nothing
+func (NopObserver) OnFire(time.Time) {}
+
+// OnComplete does nothing
+func (NopObserver) OnComplete(Result) {}
+
+// WithObserver makes o observe every change in the state of Fn
+func WithObserver(o Observer) Option {
+	return func(f *Fn) { f.obs = o }
+}
+
+func (f *Fn) observer() Observer {
+	if f.obs == nil {
+		return NopObserver{}
+	}
+	return f.obs
+}
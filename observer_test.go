@@ -0,0 +1,118 @@
+package delayed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// events records what an Observer is told relative to the start of a clock
+type events struct {
+	m     sync.Mutex
+	start time.Time
+	log   []string
+}
+
+func (e *events) add(format string, v ...interface{}) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.log = append(e.log, fmt.Sprintf(format, v...))
+}
+
+func (e *events) logged() []string {
+	e.m.Lock()
+	defer e.m.Unlock()
+	return append([]string(nil), e.log...)
+}
+
+func (e *events) OnSchedule(deadline time.Time) {
+	e.add("schedule %v", deadline.Sub(e.start))
+}
+
+func (e *events) OnReschedule(previous, deadline time.Time) {
+	e.add("reschedule %v -> %v", previous.Sub(e.start), deadline.Sub(e.start))
+}
+
+func (e *events) OnCancel() {
+	e.add("cancel")
+}
+
+func (e *events) OnFire(started time.Time) {
+	e.add("fire %v", started.Sub(e.start))
+}
+
+func (e *events) OnComplete(r Result) {
+	e.add("complete %v %v", r.Duration(), r.Err)
+}
+
+func TestObserver(t *testing.T) {
+	c := clock.NewFake()
+	e := &events{start: c.Now()}
+	failed := errors.New("failed")
+
+	fn := &Fn{}
+	WithClock(c)(fn)
+	WithObserver(e)(fn)
+
+	fn.ResetE(time.Second, func() error { return failed })
+	fn.ResetDelay(2 * time.Second)
+	fn.Cancel()
+	fn.Cancel()
+
+	fn.Call()
+	c.Advance(time.Second)
+	fn.Extend(time.Second)
+	c.Advance(2 * time.Second)
+	fn.Wait(context.Background())
+
+	assert.Equal(t, []string{
+		"schedule 1s",
+		"reschedule 1s -> 2s",
+		"cancel",
+		"schedule 2s",
+		"reschedule 2s -> 3s",
+		"fire 3s",
+		"complete 0s failed",
+	}, e.logged())
+}
+
+func TestObserver_invalid_reset(t *testing.T) {
+	c := clock.NewFake()
+	e := &events{start: c.Now()}
+
+	fn := NewFn(time.Second, func() {}, WithClock(c), WithObserver(e))
+	fn.Call()
+	assert.Error(t, fn.ResetDelay(-1))
+
+	assert.Equal(t, []string{"schedule 1s", "cancel"}, e.logged())
+	assert.Equal(t, StateCancelled, fn.State())
+}
+
+// fires only counts the runs that start
+type fires struct {
+	NopObserver
+	started chan time.Time
+}
+
+func (o fires) OnFire(started time.Time) {
+	o.started <- started
+}
+
+func TestNopObserver(t *testing.T) {
+	c := clock.NewFake()
+	o := fires{started: make(chan time.Time, 1)}
+
+	fn := NewFn(time.Second, func() {}, WithClock(c), WithObserver(o))
+	fn.Call()
+	fn.ResetDelay(2 * time.Second)
+	c.Advance(2 * time.Second)
+	fn.Wait(context.Background())
+
+	assert.Equal(t, c.Now(), <-o.started)
+}
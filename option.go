@@ -0,0 +1,24 @@
+package delayed
+
+import (
+	"github.com/sthaha/delayed/clock"
+)
+
+// Option configures a Fn when it is created
+type Option func(*Fn)
+
+// WithErrorHandler sets h to be called with the error of every failed run
+func WithErrorHandler(h func(error)) Option {
+	return func(f *Fn) { f.onError = h }
+}
+
+// WithAsyncFlush makes Flush run the function in its own goroutine instead
+// of waiting for it to complete
+func WithAsyncFlush() Option {
+	return func(f *Fn) { f.asyncFlush = true }
+}
+
+// WithClock makes Fn use c instead of the time package to schedule runs
+func WithClock(c clock.Clock) Option {
+	return func(f *Fn) { f.clock = c }
+}
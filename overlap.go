@@ -0,0 +1,37 @@
+package delayed
+
+// OverlapPolicy decides what happens when a run is due while the previous
+// one is still in progress
+type OverlapPolicy int
+
+const (
+	// OverlapConcurrent runs both at the same time; the context of the
+	// previous run is cancelled when it is superseded
+	OverlapConcurrent OverlapPolicy = iota
+
+	// OverlapSerialize starts the new run once the previous one completes
+	OverlapSerialize
+
+	// OverlapSkip drops the new run
+	OverlapSkip
+
+	// OverlapSupersede cancels the context of the previous run and starts
+	// the new run once the previous one returns
+	OverlapSupersede
+)
+
+// WithOverlap sets what happens when a run is due while the previous one is
+// still in progress
+func WithOverlap(p OverlapPolicy) Option {
+	return func(f *Fn) { f.overlap = p }
+}
+
+// serial is true if runs must not overlap
+func (p OverlapPolicy) serial() bool {
+	return p == OverlapSerialize || p == OverlapSupersede
+}
+
+// supersedes is true if a new run cancels the context of a run in progress
+func (p OverlapPolicy) supersedes() bool {
+	return p == OverlapConcurrent || p == OverlapSupersede
+}
@@ -0,0 +1,114 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tracker records how many runs overlap and how each of them ended
+type tracker struct {
+	m         sync.Mutex
+	active    int
+	overlap   int
+	runs      []int
+	cancelled []int
+}
+
+func (tr *tracker) fn(version int, d time.Duration) func(context.Context) {
+	return func(ctx context.Context) {
+		tr.m.Lock()
+		tr.active++
+		if tr.active > tr.overlap {
+			tr.overlap = tr.active
+		}
+		tr.runs = append(tr.runs, version)
+		tr.m.Unlock()
+
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			tr.m.Lock()
+			tr.cancelled = append(tr.cancelled, version)
+			tr.m.Unlock()
+		}
+
+		tr.m.Lock()
+		tr.active--
+		tr.m.Unlock()
+	}
+}
+
+// resetWhileRunning starts version 1 and resets f to version 2 while
+// version 1 is still running
+func resetWhileRunning(f *Fn, tr *tracker) {
+	f.ResetContext(context.Background(), 10*time.Millisecond, tr.fn(1, 60*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	f.ResetContext(context.Background(), 10*time.Millisecond, tr.fn(2, 10*time.Millisecond))
+	f.Wait(context.Background())
+}
+
+func TestOverlap_concurrent(t *testing.T) {
+	tr := &tracker{}
+	resetWhileRunning(NewFn(0, nil), tr)
+
+	assert.Equal(t, []int{1, 2}, tr.runs)
+	assert.Equal(t, []int{1}, tr.cancelled, "superseded run must be cancelled")
+}
+
+func TestOverlap_serialize(t *testing.T) {
+	tr := &tracker{}
+	fn := NewFn(0, nil, WithOverlap(OverlapSerialize))
+	resetWhileRunning(fn, tr)
+
+	assert.Equal(t, 1, tr.overlap, "runs must not overlap")
+	assert.Equal(t, []int{1, 2}, tr.runs)
+	assert.Empty(t, tr.cancelled, "previous run must complete")
+}
+
+func TestOverlap_skip(t *testing.T) {
+	tr := &tracker{}
+	fn := NewFn(0, nil, WithOverlap(OverlapSkip))
+	resetWhileRunning(fn, tr)
+
+	assert.Equal(t, []int{1}, tr.runs, "new run must be dropped")
+	assert.Empty(t, tr.cancelled, "previous run must complete")
+	assert.Equal(t, 1, fn.Fires())
+}
+
+func TestOverlap_supersede(t *testing.T) {
+	tr := &tracker{}
+	fn := NewFn(0, nil, WithOverlap(OverlapSupersede))
+	resetWhileRunning(fn, tr)
+
+	assert.Equal(t, 1, tr.overlap, "runs must not overlap")
+	assert.Equal(t, []int{1, 2}, tr.runs)
+	assert.Equal(t, []int{1}, tr.cancelled, "superseded run must be cancelled")
+}
+
+func TestOverlap_concurrent_resets(t *testing.T) {
+	for _, p := range []OverlapPolicy{OverlapConcurrent, OverlapSerialize, OverlapSkip, OverlapSupersede} {
+		tr := &tracker{}
+		fn := NewFn(0, nil, WithOverlap(p))
+
+		wg := &sync.WaitGroup{}
+		for i := 1; i <= 5; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				fn.ResetContext(context.Background(), time.Millisecond, tr.fn(i, 5*time.Millisecond))
+			}(i)
+			time.Sleep(2 * time.Millisecond)
+		}
+		wg.Wait()
+		fn.Wait(context.Background())
+
+		if p.serial() {
+			assert.Equal(t, 1, tr.overlap, "runs must not overlap")
+		}
+		assert.NotEmpty(t, tr.runs)
+	}
+}
@@ -0,0 +1,76 @@
+package delayed
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// PanicPolicy decides what happens when a delayed function panics
+type PanicPolicy int32
+
+const (
+	// PanicDefault follows the package wide policy set by SetPanicPolicy
+	PanicDefault PanicPolicy = iota
+
+	// PanicCrash lets the panic crash the process
+	PanicCrash
+
+	// PanicReport recovers and reports a *PanicError as the error of the run
+	PanicReport
+
+	// PanicReschedule recovers, reports a *PanicError as the error of the run and
+	// schedules the function again with the same delay
+	PanicReschedule
+)
+
+var defaultPanicPolicy int32
+
+// SetPanicPolicy sets the policy of every Fn that is not configured using
+// WithPanicPolicy. PanicCrash is used if it is never set.
+func SetPanicPolicy(p PanicPolicy) {
+	atomic.StoreInt32(&defaultPanicPolicy, int32(p))
+}
+
+// WithPanicPolicy sets what happens when the function of Fn panics
+func WithPanicPolicy(p PanicPolicy) Option {
+	return func(f *Fn) { f.panics = p }
+}
+
+// PanicError is the error reported when a panic of a delayed function is
+// recovered
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("delayed function panicked: %v", e.Value)
+}
+
+func (f *Fn) panicPolicy() PanicPolicy {
+	if f.panics != PanicDefault {
+		return f.panics
+	}
+
+	if p := PanicPolicy(atomic.LoadInt32(&defaultPanicPolicy)); p != PanicDefault {
+		return p
+	}
+	return PanicCrash
+}
+
+// invoke runs fn, recovering from a panic unless the policy is PanicCrash
+func invoke(ctx context.Context, fn func(context.Context) error, policy PanicPolicy) (err error) {
+	if policy != PanicCrash {
+		defer func() {
+			if v := recover(); v != nil {
+				stack := make([]byte, 64<<10)
+				stack = stack[:runtime.Stack(stack, false)]
+				err = &PanicError{Value: v, Stack: stack}
+			}
+		}()
+	}
+
+	return fn(ctx)
+}
@@ -0,0 +1,110 @@
+package delayed
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanic_report(t *testing.T) {
+	handled := make(chan error, 1)
+	fn, err := Call(10*time.Millisecond, func() {
+		panic("boom")
+	}, WithPanicPolicy(PanicReport), WithErrorHandler(func(err error) { handled <- err }))
+	assert.NoError(t, err, "must be created")
+
+	select {
+	case err := <-handled:
+		p, ok := err.(*PanicError)
+		assert.True(t, ok, "must report a PanicError")
+		assert.Equal(t, "boom", p.Value)
+		assert.NotEmpty(t, p.Stack)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("panic was not reported")
+	}
+
+	r, ok := fn.LastResult()
+	assert.True(t, ok)
+	assert.IsType(t, &PanicError{}, r.Err)
+}
+
+func TestPanic_reschedule(t *testing.T) {
+	var calls int32
+	fn, err := Call(10*time.Millisecond, func() {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			panic("boom")
+		}
+	}, WithPanicPolicy(PanicReschedule))
+	assert.NoError(t, err, "must be created")
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "must run until it does not panic")
+
+	r, _ := fn.LastResult()
+	assert.NoError(t, r.Err)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "must not run again")
+}
+
+func TestPanic_reschedule_cancelled(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	fn := NewFn(10*time.Millisecond, func() {
+		atomic.AddInt32(&calls, 1)
+		panic("boom")
+	}, WithPanicPolicy(PanicReschedule), WithClock(c))
+
+	fn.Call()
+	c.Advance(10 * time.Millisecond)
+
+	// the timer of the first run is gone once it fired
+	c.BlockUntilTimers(1)
+	assert.True(t, fn.Cancel(), "the retry must be pending")
+
+	c.Advance(time.Second)
+	fn.Wait(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "cancel must stop rescheduling")
+	assert.Empty(t, c.Pending())
+}
+
+func TestPanic_reschedule_future_cancelled(t *testing.T) {
+	fut := After(time.Hour, func() (int, error) { return 42, nil }, WithPanicPolicy(PanicReschedule))
+
+	// model a run that fired and panicked but is not rescheduled yet
+	fut.fn.Cancel()
+	fut.start()
+	fut.restart()
+
+	assert.True(t, fut.Cancel(), "a retry must be cancellable")
+	_, err := fut.Get(context.Background())
+	assert.Equal(t, ErrCancelled, err)
+
+	fut = After(time.Hour, func() (int, error) { return 42, nil }, WithPanicPolicy(PanicReschedule))
+	fut.fn.Cancel()
+	fut.start()
+	fut.restart()
+
+	assert.NoError(t, fut.Reschedule(time.Millisecond), "a retry must be reschedulable")
+	v, err := fut.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+}
+
+func TestSetPanicPolicy(t *testing.T) {
+	SetPanicPolicy(PanicReport)
+	defer SetPanicPolicy(PanicDefault)
+
+	fn := &Fn{}
+	assert.Equal(t, PanicReport, fn.panicPolicy())
+
+	fn = NewFn(time.Millisecond, func() {}, WithPanicPolicy(PanicCrash))
+	assert.Equal(t, PanicCrash, fn.panicPolicy(), "option must override the default")
+
+	SetPanicPolicy(PanicDefault)
+	assert.Equal(t, PanicCrash, (&Fn{}).panicPolicy())
+}
@@ -0,0 +1,33 @@
+package delayed
+
+// Pause suspends the countdown of the pending run; returns false if nothing
+// is pending. Waiters keep waiting while f is paused.
+func (f *Fn) Pause() bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if !f.hold() {
+		return false
+	}
+
+	f.remaining = nonNegative(f.deadline.Sub(f.now()))
+	debug("pausing delayed call with %v remaining", f.remaining)
+	f.held = false
+	f.pending = false
+	f.paused = true
+	return true
+}
+
+// Resume continues the countdown suspended by Pause with the time that was
+// remaining; returns false if f is not paused
+func (f *Fn) Resume() bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if !f.paused {
+		return false
+	}
+
+	f.schedule(f.remaining)
+	return true
+}
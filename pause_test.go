@@ -0,0 +1,69 @@
+package delayed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPause_resume(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(60*time.Millisecond, s.Hook(testutils.Args{"version": 1}))
+
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, fn.Pause())
+	assert.Equal(t, StatePaused, fn.State())
+	remaining := fn.Remaining()
+	assert.True(t, remaining > 0 && remaining <= 30*time.Millisecond)
+
+	// the countdown must be frozen
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, 0, s.Called(), "must not run while paused")
+	assert.Equal(t, remaining, fn.Remaining())
+
+	resumed := time.Now()
+	assert.True(t, fn.Resume())
+	assert.Equal(t, StatePending, fn.State())
+	assert.False(t, fn.Resume(), "must not resume twice")
+
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called(), "must run once resumed")
+	assert.True(t, time.Since(resumed) < 60*time.Millisecond, "must run after the remaining delay")
+}
+
+func TestPause_nothing_pending(t *testing.T) {
+	fn := &Fn{}
+	assert.False(t, fn.Pause())
+	assert.False(t, fn.Resume())
+}
+
+func TestPause_wait(t *testing.T) {
+	fn, _ := Call(10*time.Millisecond, func() {})
+	fn.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, fn.Wait(ctx), "paused call must keep waiters waiting")
+}
+
+func TestPause_cancel_and_reset(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(10*time.Millisecond, s.Hook(testutils.Args{"version": 1}))
+	fn.Pause()
+
+	assert.True(t, fn.Cancel(), "paused call must be cancelled")
+	assert.Equal(t, StateCancelled, fn.State())
+	assert.False(t, fn.Resume())
+
+	fn.Reset(time.Second, s.Hook(testutils.Args{"version": 2}))
+	fn.Pause()
+	fn.ResetDelay(10 * time.Millisecond)
+	assert.Equal(t, StatePending, fn.State(), "reset must end the pause")
+
+	fn.Wait(context.Background())
+	assert.Equal(t, 1, s.Called())
+	assert.Equal(t, 2, s.Args()["version"])
+}
@@ -0,0 +1,76 @@
+package delayed
+
+import (
+	"time"
+)
+
+// ErrCallable is a Callable whose function may fail; the outcome of the
+// last run can be inspected through LastResult
+type ErrCallable interface {
+	Callable
+	ResetE(time.Duration, func() error) error
+	ResetFuncE(func() error) error
+	LastResult() (Result, bool)
+}
+
+// Result is the outcome of a single run of a delayed function
+type Result struct {
+	Err      error
+	Started  time.Time
+	Finished time.Time
+}
+
+// Duration returns how long the run took
+func (r Result) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
+
+// CallE executes a fn that may fail after the duration and returns a handle
+// to the function so that it can be cancelled or overridden
+func CallE(d time.Duration, fn func() error, opts ...Option) (*Fn, error) {
+	f := newFn(d, plainE(fn), opts)
+	if err := f.Call(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// ResetE resets both duration and the fn that may fail to call.
+func (f *Fn) ResetE(d time.Duration, fn func() error) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	f.d = d
+	f.fn = plainE(fn)
+	return f.call()
+}
+
+// ResetFuncE resets the func that may fail to be invoked
+func (f *Fn) ResetFuncE(fn func() error) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	f.fn = plainE(fn)
+	return f.call()
+}
+
+// LastResult returns the outcome of the most recently completed run; false
+// is returned if no run has completed yet
+func (f *Fn) LastResult() (Result, bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.last == nil {
+		return Result{}, false
+	}
+	return *f.last, true
+}
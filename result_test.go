@@ -0,0 +1,67 @@
+package delayed
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallE(t *testing.T) {
+	failed := errors.New("failed")
+
+	handled := make(chan error, 1)
+	fn, err := CallE(10*time.Millisecond, func() error {
+		return failed
+	}, WithErrorHandler(func(err error) { handled <- err }))
+	assert.NoError(t, err, "must be created")
+
+	_, ok := fn.LastResult()
+	assert.False(t, ok, "must not have a result before running")
+
+	time.Sleep(30 * time.Millisecond)
+
+	r, ok := fn.LastResult()
+	assert.True(t, ok, "must have a result after running")
+	assert.Equal(t, failed, r.Err)
+	assert.False(t, r.Started.IsZero())
+	assert.False(t, r.Finished.Before(r.Started))
+
+	assert.Equal(t, failed, <-handled, "error handler must be called")
+}
+
+func TestCallE_invalid(t *testing.T) {
+	_, err := CallE(-1*time.Millisecond, func() error { return nil })
+	assert.Error(t, err, "invoking CallE with invalid args must return error")
+
+	var invalidFn func() error
+	_, err = CallE(10*time.Millisecond, invalidFn)
+	assert.Error(t, err, "invoking CallE with invalid args must return error")
+}
+
+func TestResetE_last_result(t *testing.T) {
+	var c ErrCallable = &Fn{}
+
+	assert.NoError(t, c.ResetE(10*time.Millisecond, func() error { return nil }))
+	time.Sleep(20 * time.Millisecond)
+
+	r, ok := c.LastResult()
+	assert.True(t, ok)
+	assert.NoError(t, r.Err)
+
+	failed := errors.New("failed")
+	assert.NoError(t, c.ResetFuncE(func() error { return failed }))
+	time.Sleep(20 * time.Millisecond)
+
+	r, ok = c.LastResult()
+	assert.True(t, ok)
+	assert.Equal(t, failed, r.Err)
+
+	// a plain func never fails
+	assert.NoError(t, c.ResetFunc(func() {}))
+	time.Sleep(20 * time.Millisecond)
+
+	r, _ = c.LastResult()
+	assert.NoError(t, r.Err)
+}
@@ -0,0 +1,114 @@
+package delayed
+
+import (
+	"time"
+)
+
+// State describes what a Fn is currently doing
+type State int
+
+const (
+	// StateIdle means nothing was ever scheduled
+	StateIdle State = iota
+
+	// StatePending means a run is scheduled and waiting for the delay to
+	// elapse
+	StatePending
+
+	// StateRunning means a run is in progress and nothing else is scheduled
+	StateRunning
+
+	// StateFired means the last scheduled run has completed
+	StateFired
+
+	// StateCancelled means the last scheduled run was cancelled before it
+	// could start
+	StateCancelled
+
+	// StatePaused means the countdown of the pending run is suspended
+	StatePaused
+)
+
+var stateNames = map[State]string{
+	StateIdle:      "idle",
+	StatePending:   "pending",
+	StateRunning:   "running",
+	StateFired:     "fired",
+	StateCancelled: "cancelled",
+	StatePaused:    "paused",
+}
+
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// State returns what f is currently doing
+func (f *Fn) State() State {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.state()
+}
+
+// Deadline returns when the pending run is due; the zero time is returned if
+// nothing is pending
+func (f *Fn) Deadline() time.Time {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if !f.pending {
+		return time.Time{}
+	}
+	return f.deadline
+}
+
+// Remaining returns how long until the pending run is due; while paused it
+// is the time left when Pause was called. 0 is returned if nothing is pending.
+func (f *Fn) Remaining() time.Duration {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.paused {
+		return f.remaining
+	}
+
+	if !f.pending {
+		return 0
+	}
+
+	return nonNegative(f.deadline.Sub(f.now()))
+}
+
+// Fires returns the number of runs that have started
+func (f *Fn) Fires() int {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.fires
+}
+
+// LastFired returns when the latest run started; the zero time is returned
+// if nothing has run yet
+func (f *Fn) LastFired() time.Time {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.lastFired
+}
+
+func (f *Fn) state() State {
+	switch {
+	case f.pending:
+		return StatePending
+	case f.paused:
+		return StatePaused
+	case f.running > 0:
+		return StateRunning
+	case f.cancelled:
+		return StateCancelled
+	case f.fires > 0:
+		return StateFired
+	default:
+		return StateIdle
+	}
+}
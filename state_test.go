@@ -0,0 +1,64 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState(t *testing.T) {
+	fn := &Fn{}
+	assert.Equal(t, StateIdle, fn.State())
+	assert.True(t, fn.Deadline().IsZero())
+	assert.Equal(t, time.Duration(0), fn.Remaining())
+
+	release := make(chan struct{})
+	before := time.Now()
+	fn.Reset(20*time.Millisecond, func() { <-release })
+	assert.Equal(t, StatePending, fn.State())
+	assert.False(t, fn.Deadline().Before(before.Add(20*time.Millisecond)))
+	assert.True(t, fn.Remaining() > 0 && fn.Remaining() <= 20*time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, StateRunning, fn.State())
+	assert.Equal(t, 1, fn.Fires())
+	assert.False(t, fn.LastFired().Before(before))
+	assert.True(t, fn.Deadline().IsZero(), "nothing must be pending while running")
+
+	close(release)
+	fn.Wait(context.Background())
+	assert.Equal(t, StateFired, fn.State())
+	assert.Equal(t, "fired", fn.State().String())
+
+	fn.ResetDelay(time.Second)
+	assert.Equal(t, StatePending, fn.State())
+	fn.Cancel()
+	assert.Equal(t, StateCancelled, fn.State())
+	assert.Equal(t, 1, fn.Fires())
+}
+
+func TestState_concurrent_reset(t *testing.T) {
+	fn := &Fn{}
+	wg := &sync.WaitGroup{}
+
+	for i := 1; i <= 5; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			fn.Reset(time.Duration(i)*time.Millisecond, func() {})
+		}(i)
+		go func() {
+			defer wg.Done()
+			fn.State()
+			fn.Remaining()
+			fn.Deadline()
+		}()
+	}
+	wg.Wait()
+
+	fn.Wait(context.Background())
+	assert.Equal(t, StateFired, fn.State())
+}
@@ -0,0 +1,55 @@
+package delayed
+
+import (
+	"context"
+)
+
+// StopResult describes what was stopped by Stop
+type StopResult struct {
+	// WasPending is true if a run was cancelled before it could start
+	WasPending bool
+
+	// WasRunning is true if a run was in progress
+	WasRunning bool
+
+	// Completed is true if the runs in progress completed before Stop
+	// returned
+	Completed bool
+
+	// TimedOut is true if ctx was done before the runs in progress completed
+	TimedOut bool
+}
+
+// Stop cancels the pending run and waits for runs in progress to complete
+// unless ctx is done first. The context of the runs in progress is cancelled
+// so that they can return early. A Reset while Stop waits extends the wait
+// to the new run.
+func (f *Fn) Stop(ctx context.Context) StopResult {
+	f.m.Lock()
+	r := StopResult{
+		WasRunning: f.running > 0,
+		WasPending: f.cancel(),
+	}
+
+	// a run that could not be cancelled has already started
+	if f.pending && !r.WasPending {
+		r.WasRunning = true
+	}
+	f.settle()
+
+	done := f.done
+	f.m.Unlock()
+
+	if done == nil {
+		return r
+	}
+
+	debug("waiting for delayed call to complete")
+	select {
+	case <-done:
+		r.Completed = true
+	case <-ctx.Done():
+		r.TimedOut = true
+	}
+	return r
+}
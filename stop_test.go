@@ -0,0 +1,105 @@
+package delayed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+	"github.com/sthaha/delayed/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStop_idle(t *testing.T) {
+	fn := &Fn{}
+	assert.Equal(t, StopResult{}, fn.Stop(context.Background()))
+}
+
+func TestStop_pending(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(50*time.Millisecond, s.Hook(testutils.Args{"version": 1}))
+
+	r := fn.Stop(context.Background())
+	assert.Equal(t, StopResult{WasPending: true}, r)
+
+	time.Sleep(80 * time.Millisecond)
+	assert.Equal(t, 0, s.Called(), "must not be called")
+}
+
+func TestStop_running(t *testing.T) {
+	s := &testutils.Spy{}
+	hook := s.Hook(testutils.Args{"version": 1})
+
+	fn, _ := CallContext(context.Background(), 10*time.Millisecond, func(ctx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+		hook()
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	r := fn.Stop(context.Background())
+	assert.Equal(t, StopResult{WasRunning: true, Completed: true}, r)
+	assert.Equal(t, 1, s.Called(), "running func must have completed")
+}
+
+func TestStop_timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	fn, _ := Call(10*time.Millisecond, func() { <-release })
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	r := fn.Stop(ctx)
+	assert.Equal(t, StopResult{WasRunning: true, TimedOut: true}, r)
+}
+
+func TestStop_after_flush(t *testing.T) {
+	started := make(chan struct{})
+	fn := newFn(time.Hour, contextual(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+	}), []Option{WithAsyncFlush()})
+
+	// the run is never scheduled through the timer, as on the leading edge
+	// of a Debouncer
+	fn.callNow()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	r := fn.Stop(ctx)
+	assert.Equal(t, StopResult{WasRunning: true, Completed: true}, r, "stop must cancel the flushed run")
+}
+
+func TestStop_superseded_run(t *testing.T) {
+	policies := []OverlapPolicy{OverlapConcurrent, OverlapSerialize, OverlapSkip, OverlapSupersede}
+
+	for _, policy := range policies {
+		c := clock.NewFake()
+		started := make(chan struct{}, 1)
+		fn := newFn(time.Second, contextual(func(ctx context.Context) {
+			started <- struct{}{}
+			<-ctx.Done()
+		}), []Option{WithOverlap(policy), WithClock(c)})
+
+		fn.Call()
+		c.Advance(time.Second)
+		<-started
+
+		// the older run is still in progress while a newer one is pending
+		fn.ResetDelay(time.Hour)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		r := fn.Stop(ctx)
+		cancel()
+
+		assert.Equal(t, StopResult{WasPending: true, WasRunning: true, Completed: true}, r,
+			"stop must cancel every run in progress with overlap policy %d", policy)
+	}
+}
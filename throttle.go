@@ -0,0 +1,101 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ThrottleOptions configures a Throttler
+type ThrottleOptions struct {
+	// Trailing makes the Throttler call the func once more at the end of an
+	// interval if it was triggered during the interval
+	Trailing bool
+}
+
+// Throttler calls its func at most once per interval however often it is
+// triggered
+type Throttler struct {
+	m        sync.Mutex
+	interval time.Duration
+	trailing bool
+	fn       *Fn
+	do       func()
+
+	// next is when the current interval ends; pending is true while a
+	// trailing call is scheduled for it
+	next    time.Time
+	pending bool
+}
+
+// NewThrottler returns a Throttler that calls fn at most once per interval.
+// opts configure the Fn that calls fn.
+func NewThrottler(interval time.Duration, fn func(), o ThrottleOptions, opts ...Option) *Throttler {
+	t := &Throttler{
+		interval: interval,
+		trailing: o.Trailing,
+		do:       fn,
+	}
+
+	var call func()
+	if fn != nil {
+		call = t.call
+	}
+	t.fn = NewFn(interval, call, opts...)
+	return t
+}
+
+// Trigger calls the func right away unless it was called less than an
+// interval ago, in which case the trigger is dropped or, with Trailing, the
+// func is scheduled for the end of the interval
+func (t *Throttler) Trigger() error {
+	t.m.Lock()
+	now := t.fn.now()
+	if !now.Before(t.next) {
+		t.next = now.Add(t.interval)
+		t.m.Unlock()
+		debug("throttle: calling right away")
+		return t.fn.callNow()
+	}
+	defer t.m.Unlock()
+
+	if !t.trailing || t.pending {
+		return nil
+	}
+
+	debug("throttle: calling at the end of the interval")
+	t.pending = true
+	return t.fn.ResetAt(t.next)
+}
+
+// call calls the func and starts the next interval
+func (t *Throttler) call() {
+	t.m.Lock()
+	t.pending = false
+	t.next = t.fn.now().Add(t.interval)
+	t.m.Unlock()
+
+	t.do()
+}
+
+// Flush calls the func right away if a trailing call is pending; returns
+// whether it did so
+func (t *Throttler) Flush() bool {
+	return t.fn.FlushIfPending()
+}
+
+// Cancel cancels the pending trailing call; returns false if nothing is
+// pending
+func (t *Throttler) Cancel() bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.pending = false
+	return t.fn.Cancel()
+}
+
+// Wait blocks until the pending call completes or is cancelled unless ctx
+// is done first
+func (t *Throttler) Wait(ctx context.Context) error {
+	return t.fn.Wait(ctx)
+}
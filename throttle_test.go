@@ -0,0 +1,92 @@
+package delayed_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed"
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottler(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	th := delayed.NewThrottler(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.ThrottleOptions{}, delayed.WithClock(c))
+
+	th.Trigger()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "must call on the first trigger")
+
+	for i := 0; i < 4; i++ {
+		c.Advance(300 * time.Millisecond)
+		th.Trigger()
+	}
+	// triggered at 0s, 0.3s, 0.6s, 0.9s, 1.2s
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "must call once per interval")
+
+	c.Advance(2 * time.Second)
+	th.Wait(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "must not call at the end of the interval")
+}
+
+func TestThrottler_trailing(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	th := delayed.NewThrottler(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.ThrottleOptions{Trailing: true}, delayed.WithClock(c))
+
+	th.Trigger()
+	c.Advance(300 * time.Millisecond)
+	th.Trigger()
+	th.Trigger()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	c.Advance(700 * time.Millisecond)
+	th.Wait(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "must call once at the end of the interval")
+
+	// the trailing call starts a new interval
+	c.Advance(500 * time.Millisecond)
+	th.Trigger()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.True(t, th.Cancel())
+	c.Advance(time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	th.Trigger()
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "must call right away after a quiet interval")
+}
+
+func TestThrottler_flush(t *testing.T) {
+	c := clock.NewFake()
+	var calls int32
+	th := delayed.NewThrottler(time.Second, func() { atomic.AddInt32(&calls, 1) },
+		delayed.ThrottleOptions{Trailing: true}, delayed.WithClock(c))
+
+	assert.False(t, th.Flush(), "nothing to flush")
+	th.Trigger()
+	th.Trigger()
+	assert.True(t, th.Flush())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestThrottler_concurrent_triggers(t *testing.T) {
+	var calls int32
+	th := delayed.NewThrottler(time.Hour, func() { atomic.AddInt32(&calls, 1) },
+		delayed.ThrottleOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			th.Trigger()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,43 @@
+package delayed
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// WithTimeout limits how long every run of Fn may take. Once a run exceeds
+// d its context is cancelled with ErrTimeout as the cause, ErrTimeout is
+// reported to the error handler right away and becomes the error of the run
+// unless the function returns a different one.
+func WithTimeout(d time.Duration) Option {
+	return func(f *Fn) { f.timeout = d }
+}
+
+// budget derives the context of a run that is cancelled once the timeout of
+// f expires. The returned func releases the budget and returns whether it
+// had expired.
+func (f *Fn) budget(ctx context.Context) (context.Context, func() bool) {
+	if f.timeout <= 0 {
+		return ctx, func() bool { return false }
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	onError := f.onError
+
+	var expired int32
+	t := f.clk().AfterFunc(f.timeout, func() {
+		debug("delayed call exceeded %v", f.timeout)
+		atomic.StoreInt32(&expired, 1)
+		cancel(ErrTimeout)
+		if onError != nil {
+			onError(ErrTimeout)
+		}
+	})
+
+	return ctx, func() bool {
+		t.Stop()
+		cancel(nil)
+		return atomic.LoadInt32(&expired) == 1
+	}
+}
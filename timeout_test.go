@@ -0,0 +1,68 @@
+package delayed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	c := clock.NewFake()
+	handled := make(chan error, 2)
+	causes := make(chan error, 1)
+
+	fn, _ := CallContext(context.Background(), time.Second, func(ctx context.Context) {
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+	}, WithClock(c), WithTimeout(5*time.Second), WithErrorHandler(func(err error) { handled <- err }))
+
+	c.Advance(time.Second)
+	c.BlockUntilTimers(1)
+	assert.Equal(t, StateRunning, fn.State())
+
+	c.Advance(5 * time.Second)
+	assert.Equal(t, ErrTimeout, <-causes, "context must be cancelled by the timeout")
+	assert.Equal(t, ErrTimeout, <-handled, "overrun must be reported")
+
+	fn.Wait(context.Background())
+	r, _ := fn.LastResult()
+	assert.Equal(t, ErrTimeout, r.Err)
+	assert.True(t, errors.Is(r.Err, context.DeadlineExceeded))
+	assert.Len(t, handled, 0, "overrun must be reported once")
+}
+
+func TestWithTimeout_within_budget(t *testing.T) {
+	c := clock.NewFake()
+
+	fn, _ := CallE(time.Second, func() error { return nil }, WithClock(c), WithTimeout(5*time.Second))
+	c.Advance(time.Second)
+	fn.Wait(context.Background())
+
+	r, _ := fn.LastResult()
+	assert.NoError(t, r.Err)
+	assert.Empty(t, c.Pending(), "budget must be released")
+}
+
+func TestWithTimeout_fn_error(t *testing.T) {
+	c := clock.NewFake()
+	failed := errors.New("failed")
+	release := make(chan struct{})
+
+	fn, _ := CallE(time.Second, func() error {
+		<-release
+		return failed
+	}, WithClock(c), WithTimeout(time.Second))
+
+	c.Advance(time.Second)
+	c.BlockUntilTimers(1)
+	c.Advance(time.Second)
+	close(release)
+	fn.Wait(context.Background())
+
+	r, _ := fn.LastResult()
+	assert.Equal(t, failed, r.Err, "error of the func must be kept")
+}
@@ -0,0 +1,88 @@
+package delayed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ValueFn calls a func with the latest value it was given once some duration
+// has elapsed. Every Reset or Update replaces the pending value instead of
+// scheduling a new closure.
+type ValueFn[T any] struct {
+	fn *Fn
+
+	// fresh is set while v has not been passed to the func
+	m     sync.Mutex
+	v     T
+	fresh bool
+}
+
+// NewValueFn returns a ValueFn that calls fn after d with the latest value
+func NewValueFn[T any](d time.Duration, fn func(T), opts ...Option) *ValueFn[T] {
+	f := &ValueFn[T]{}
+
+	var run func(context.Context) error
+	if fn != nil {
+		run = func(context.Context) error {
+			// a run scheduled just after the previous one took the value
+			// has nothing to pass on
+			if v, ok := f.take(); ok {
+				fn(v)
+			}
+			return nil
+		}
+	}
+
+	f.fn = newFn(d, run, opts)
+	return f
+}
+
+// Reset resets the delay to d and replaces the pending value with v
+func (f *ValueFn[T]) Reset(d time.Duration, v T) error {
+	f.set(v)
+	return f.fn.ResetDelay(d)
+}
+
+// Update replaces the pending value with v without moving the deadline. If
+// nothing is pending, the func is scheduled to be called after the delay.
+func (f *ValueFn[T]) Update(v T) error {
+	f.set(v)
+	return f.fn.callUnlessPending()
+}
+
+// Cancel cancels the pending call; see Fn.Cancel
+func (f *ValueFn[T]) Cancel() bool {
+	return f.fn.Cancel()
+}
+
+// Flush calls the func with the latest value right away; see Fn.Flush
+func (f *ValueFn[T]) Flush() error {
+	return f.fn.Flush()
+}
+
+// Wait blocks until the pending call completes or is cancelled; see Fn.Wait
+func (f *ValueFn[T]) Wait(ctx context.Context) error {
+	return f.fn.Wait(ctx)
+}
+
+// State returns what the ValueFn is currently doing
+func (f *ValueFn[T]) State() State {
+	return f.fn.State()
+}
+
+func (f *ValueFn[T]) set(v T) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.v, f.fresh = v, true
+}
+
+// take returns the value unless it was already taken
+func (f *ValueFn[T]) take() (T, bool) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	fresh := f.fresh
+	f.fresh = false
+	return f.v, fresh
+}
@@ -0,0 +1,101 @@
+package delayed_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed"
+	"github.com/sthaha/delayed/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder keeps the values a ValueFn was called with
+type recorder struct {
+	m      sync.Mutex
+	values []string
+}
+
+func (r *recorder) record(v string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.values = append(r.values, v)
+}
+
+func (r *recorder) recorded() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]string(nil), r.values...)
+}
+
+func TestValueFn_latest_value_wins(t *testing.T) {
+	c := clock.NewFake()
+	r := &recorder{}
+	fn := delayed.NewValueFn(time.Second, r.record, delayed.WithClock(c))
+
+	fn.Reset(time.Second, "first")
+	c.Advance(500 * time.Millisecond)
+	fn.Reset(time.Second, "second")
+	c.Advance(500 * time.Millisecond)
+	assert.Equal(t, delayed.StatePending, fn.State(), "reset must restart the delay")
+
+	c.Advance(500 * time.Millisecond)
+	fn.Wait(context.Background())
+	assert.Equal(t, []string{"second"}, r.recorded())
+}
+
+func TestValueFn_update(t *testing.T) {
+	c := clock.NewFake()
+	r := &recorder{}
+	fn := delayed.NewValueFn(time.Second, r.record, delayed.WithClock(c))
+
+	assert.NoError(t, fn.Update("first"), "update must schedule when nothing is pending")
+	c.Advance(500 * time.Millisecond)
+	fn.Update("second")
+	fn.Update("third")
+
+	// update must not move the deadline
+	c.Advance(500 * time.Millisecond)
+	fn.Wait(context.Background())
+	assert.Equal(t, []string{"third"}, r.recorded())
+}
+
+func TestValueFn_flush_cancel(t *testing.T) {
+	r := &recorder{}
+	fn := delayed.NewValueFn(time.Second, r.record)
+
+	fn.Reset(time.Second, "flushed")
+	assert.NoError(t, fn.Flush())
+	assert.Equal(t, []string{"flushed"}, r.recorded())
+
+	fn.Reset(time.Second, "cancelled")
+	assert.True(t, fn.Cancel())
+	assert.Equal(t, delayed.StateCancelled, fn.State())
+
+	var invalidFn func(string)
+	assert.Error(t, delayed.NewValueFn(time.Second, invalidFn).Reset(time.Second, "v"))
+}
+
+func TestValueFn_reset_delivers_once(t *testing.T) {
+	var m sync.Mutex
+	delivered := map[int]int{}
+	fn := delayed.NewValueFn(0, func(v int) {
+		m.Lock()
+		defer m.Unlock()
+		delivered[v]++
+	})
+
+	// every run is due right away and races the next Reset
+	for i := 0; i < 1000; i++ {
+		fn.Reset(0, i)
+	}
+	fn.Wait(context.Background())
+
+	m.Lock()
+	defer m.Unlock()
+	for v, n := range delivered {
+		assert.Equal(t, 1, n, "value %d must be delivered once", v)
+	}
+	assert.Equal(t, 1, delivered[999], "the latest value must be delivered")
+}
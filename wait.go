@@ -0,0 +1,36 @@
+package delayed
+
+import (
+	"context"
+)
+
+// closed is returned by Done when there is nothing to wait for
+var closed = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// Done returns a channel that is closed once the scheduled run completes or
+// is cancelled. Resetting f before that keeps the channel open until the new
+// run completes or is cancelled.
+func (f *Fn) Done() <-chan struct{} {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.done == nil {
+		return closed
+	}
+	return f.done
+}
+
+// Wait blocks until the scheduled run completes or is cancelled, returning
+// ctx.Err() if ctx is done first
+func (f *Fn) Wait(ctx context.Context) error {
+	select {
+	case <-f.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,80 @@
+package delayed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sthaha/delayed/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait_idle(t *testing.T) {
+	fn := &Fn{}
+	assert.NoError(t, fn.Wait(context.Background()), "nothing to wait for")
+
+	select {
+	case <-fn.Done():
+	default:
+		t.Fatal("done must be closed when nothing is scheduled")
+	}
+}
+
+func TestWait_completes(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(20*time.Millisecond, s.Hook(testutils.Args{"version": 1}))
+
+	assert.NoError(t, fn.Wait(context.Background()))
+	assert.Equal(t, 1, s.Called(), "must be called once")
+}
+
+func TestWait_cancelled(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(time.Second, s.Hook(testutils.Args{"version": 1}))
+	done := fn.Done()
+
+	fn.Cancel()
+	select {
+	case <-done:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("cancel must release the waiters")
+	}
+	assert.Equal(t, 0, s.Called())
+}
+
+func TestWait_reset(t *testing.T) {
+	s := &testutils.Spy{}
+	fn, _ := Call(20*time.Millisecond, s.Hook(testutils.Args{"version": 1}))
+	done := fn.Done()
+
+	time.Sleep(10 * time.Millisecond)
+	fn.Reset(40*time.Millisecond, s.Hook(testutils.Args{"version": 2}))
+
+	<-done
+	assert.Equal(t, 1, s.Called(), "must wait for the replaced run")
+	assert.Equal(t, 2, s.Args()["version"])
+}
+
+func TestWait_running(t *testing.T) {
+	s := &testutils.Spy{}
+	hook := s.Hook(testutils.Args{"version": 1})
+	fn, _ := Call(10*time.Millisecond, func() {
+		time.Sleep(50 * time.Millisecond)
+		hook()
+	})
+
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, fn.Cancel(), "must be running")
+
+	assert.NoError(t, fn.Wait(context.Background()))
+	assert.Equal(t, 1, s.Called(), "must wait for the run in flight")
+}
+
+func TestWait_timeout(t *testing.T) {
+	fn, _ := Call(time.Second, func() {})
+	defer fn.Cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, fn.Wait(ctx))
+}